@@ -0,0 +1,327 @@
+package fine
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+)
+
+// The value an analog binding has to reach for its action to count as pressed.
+const ActionPressThreshold = 0.5
+
+// Input binding types.
+type BindingType int
+
+const (
+	BINDING_KEY            BindingType = 0 // Keyboard key.
+	BINDING_MOUSE_BUTTON   BindingType = 1 // Mouse button.
+	BINDING_GAMEPAD_BUTTON BindingType = 2 // Gamepad button (on any gamepad).
+	BINDING_GAMEPAD_AXIS   BindingType = 3 // Gamepad axis (on any gamepad).
+)
+
+var errInvalidBinding = errors.New("binding has no input")
+
+// An input bound to an action.
+type Binding struct {
+	Type          BindingType   // The type of the input.
+	Key           Key           // The key, if Type is BINDING_KEY.
+	MouseButton   MouseButton   // The mouse button, if Type is BINDING_MOUSE_BUTTON.
+	GamepadButton GamepadButton // The gamepad button, if Type is BINDING_GAMEPAD_BUTTON.
+	GamepadAxis   GamepadAxis   // The gamepad axis, if Type is BINDING_GAMEPAD_AXIS.
+	Scale         float64       // The value of the binding is multiplied by this (for example -1 for "left" in a "move_x" action). A scale of 0 is used as 1.
+}
+
+// A named input action with its bindings.
+type Action struct {
+	Name         string    // Name of the action.
+	Bindings     []Binding // All inputs bound to the action.
+	down         bool
+	justPressed  bool
+	justReleased bool
+}
+
+// JSON representation of a binding, exactly one input is set.
+type bindingJSON struct {
+	Key           *Key           `json:"key,omitempty"`
+	MouseButton   *MouseButton   `json:"mouse_button,omitempty"`
+	GamepadButton *GamepadButton `json:"gamepad_button,omitempty"`
+	GamepadAxis   *GamepadAxis   `json:"gamepad_axis,omitempty"`
+	Scale         float64        `json:"scale,omitempty"`
+}
+
+// Creates a key binding.
+func KeyBinding(key Key) Binding {
+	return Binding{Type: BINDING_KEY, Key: key, Scale: 1}
+}
+
+// Creates a mouse button binding.
+func MouseButtonBinding(button MouseButton) Binding {
+	return Binding{Type: BINDING_MOUSE_BUTTON, MouseButton: button, Scale: 1}
+}
+
+// Creates a gamepad button binding.
+func GamepadButtonBinding(button GamepadButton) Binding {
+	return Binding{Type: BINDING_GAMEPAD_BUTTON, GamepadButton: button, Scale: 1}
+}
+
+// Creates a gamepad axis binding.
+func GamepadAxisBinding(axis GamepadAxis) Binding {
+	return Binding{Type: BINDING_GAMEPAD_AXIS, GamepadAxis: axis, Scale: 1}
+}
+
+// Returns a copy of the binding with a different scale.
+func (binding Binding) WithScale(scale float64) Binding {
+	binding.Scale = scale
+	return binding
+}
+
+// Returns the current value of the binding. Digital inputs are either 0 or
+// the scale, analog inputs are between -scale and scale.
+func (binding Binding) Value(app *App) float64 {
+	switch binding.Type {
+	case BINDING_KEY:
+		if app.IsKeyDown(binding.Key) {
+			return binding.scale()
+		}
+	case BINDING_MOUSE_BUTTON:
+		if app.IsMouseButtonDown(binding.MouseButton) {
+			return binding.scale()
+		}
+	case BINDING_GAMEPAD_BUTTON:
+		if app.IsGamepadButtonDown(binding.GamepadButton) {
+			return binding.scale()
+		}
+	case BINDING_GAMEPAD_AXIS:
+		// Use the gamepad with the largest deflection
+		value := 0.0
+		for _, gamepad := range app.Gamepads {
+			if axisValue := gamepad.GetAxis(binding.GamepadAxis); math.Abs(axisValue) > math.Abs(value) {
+				value = axisValue
+			}
+		}
+		return value * binding.scale()
+	}
+	return 0
+}
+
+// Returns the scale of the binding, bindings without a scale are not scaled.
+func (binding Binding) scale() float64 {
+	if binding.Scale == 0 {
+		return 1
+	}
+	return binding.Scale
+}
+
+// Encodes the binding as a JSON object with one of the "key", "mouse_button",
+// "gamepad_button" or "gamepad_axis" fields and an optional "scale".
+func (binding Binding) MarshalJSON() ([]byte, error) {
+	data := bindingJSON{}
+	if scale := binding.scale(); scale != 1 {
+		data.Scale = scale
+	}
+
+	switch binding.Type {
+	case BINDING_KEY:
+		data.Key = &binding.Key
+	case BINDING_MOUSE_BUTTON:
+		data.MouseButton = &binding.MouseButton
+	case BINDING_GAMEPAD_BUTTON:
+		data.GamepadButton = &binding.GamepadButton
+	case BINDING_GAMEPAD_AXIS:
+		data.GamepadAxis = &binding.GamepadAxis
+	default:
+		return nil, errInvalidBinding
+	}
+	return json.Marshal(data)
+}
+
+// Decodes a binding from JSON, see binding.MarshalJSON.
+func (binding *Binding) UnmarshalJSON(text []byte) error {
+	var data bindingJSON
+	if err := json.Unmarshal(text, &data); err != nil {
+		return err
+	}
+
+	switch {
+	case data.Key != nil:
+		*binding = KeyBinding(*data.Key)
+	case data.MouseButton != nil:
+		*binding = MouseButtonBinding(*data.MouseButton)
+	case data.GamepadButton != nil:
+		*binding = GamepadButtonBinding(*data.GamepadButton)
+	case data.GamepadAxis != nil:
+		*binding = GamepadAxisBinding(*data.GamepadAxis)
+	default:
+		return errInvalidBinding
+	}
+
+	if data.Scale != 0 {
+		binding.Scale = data.Scale
+	}
+	return nil
+}
+
+// Adds bindings to an action. The action is created if it doesn't exist.
+func (app *App) BindAction(name string, bindings ...Binding) *App {
+	action, ok := app.Actions[name]
+	if !ok {
+		action = &Action{Name: name}
+		app.Actions[name] = action
+	}
+	action.Bindings = append(action.Bindings, bindings...)
+	return app
+}
+
+// Replaces all bindings of an action. The action is created if it doesn't exist.
+func (app *App) RebindAction(name string, bindings ...Binding) *App {
+	if action, ok := app.Actions[name]; ok {
+		action.Bindings = nil
+	}
+	return app.BindAction(name, bindings...)
+}
+
+// Removes an action and all of its bindings.
+func (app *App) RemoveAction(name string) *App {
+	delete(app.Actions, name)
+	return app
+}
+
+// Returns an action, or nil if it doesn't exist.
+func (app *App) GetAction(name string) *Action {
+	return app.Actions[name]
+}
+
+// Checks if any input bound to an action is currently pressed.
+func (app *App) IsActionDown(name string) bool {
+	action, ok := app.Actions[name]
+	return ok && action.down
+}
+
+// Checks if an action was pressed on this frame.
+func (app *App) IsActionJustPressed(name string) bool {
+	action, ok := app.Actions[name]
+	return ok && action.justPressed
+}
+
+// Checks if an action was released on this frame.
+func (app *App) IsActionJustReleased(name string) bool {
+	action, ok := app.Actions[name]
+	return ok && action.justReleased
+}
+
+// Returns the value of an action between -1 and 1, which is the sum of the
+// values of all its bindings. Useful for movement axes.
+func (app *App) GetActionValue(name string) float64 {
+	action, ok := app.Actions[name]
+	if !ok {
+		return 0
+	}
+	return action.Value(app)
+}
+
+// Returns the value of the action between -1 and 1, see app.GetActionValue.
+func (action *Action) Value(app *App) float64 {
+	value := 0.0
+	for _, binding := range action.Bindings {
+		value += binding.Value(app)
+	}
+	return math.Max(-1, math.Min(1, value))
+}
+
+// Checks if the input of a digital binding was pressed on this frame.
+func (binding Binding) justDown(app *App) bool {
+	switch binding.Type {
+	case BINDING_KEY:
+		return app.IsKeyJustDown(binding.Key)
+	case BINDING_MOUSE_BUTTON:
+		return app.IsMouseButtonJustDown(binding.MouseButton)
+	case BINDING_GAMEPAD_BUTTON:
+		return app.IsGamepadButtonJustDown(binding.GamepadButton)
+	}
+	return false
+}
+
+// Checks if the input of a digital binding was released on this frame.
+func (binding Binding) justUp(app *App) bool {
+	switch binding.Type {
+	case BINDING_KEY:
+		return app.IsKeyJustUp(binding.Key)
+	case BINDING_MOUSE_BUTTON:
+		return app.IsMouseButtonJustUp(binding.MouseButton)
+	case BINDING_GAMEPAD_BUTTON:
+		return app.IsGamepadButtonJustUp(binding.GamepadButton)
+	}
+	return false
+}
+
+// Updates the pressed state of all actions. This is called every frame
+// after the input events are handled.
+func (app *App) updateActions() {
+	for _, action := range app.Actions {
+		down, pressed, released := false, false, false
+		for _, binding := range action.Bindings {
+			if math.Abs(binding.Value(app)) >= ActionPressThreshold {
+				down = true
+			}
+			// An input can be pressed and released between two frames, so
+			// the events are checked too
+			pressed = pressed || binding.justDown(app)
+			released = released || binding.justUp(app)
+		}
+
+		action.justPressed = !action.down && (down || pressed)
+		action.justReleased = !down && (action.down || released)
+		action.down = down
+	}
+}
+
+// Saves the bindings of all actions as JSON.
+func (app *App) SaveBindings(writer io.Writer) error {
+	bindings := make(map[string][]Binding, len(app.Actions))
+	for name, action := range app.Actions {
+		bindings[name] = action.Bindings
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(bindings)
+}
+
+// Saves the bindings of all actions to a JSON file.
+func (app *App) SaveBindingsToFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := app.SaveBindings(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Loads action bindings from JSON saved with app.SaveBindings. Actions in the
+// JSON replace the bindings of existing actions, other actions are unchanged.
+func (app *App) LoadBindings(reader io.Reader) error {
+	var bindings map[string][]Binding
+	if err := json.NewDecoder(reader).Decode(&bindings); err != nil {
+		return err
+	}
+
+	for name, actionBindings := range bindings {
+		app.RebindAction(name, actionBindings...)
+	}
+	return nil
+}
+
+// Loads action bindings from a JSON file, see app.LoadBindings.
+func (app *App) LoadBindingsFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return app.LoadBindings(file)
+}
@@ -0,0 +1,60 @@
+package fine
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBindingZeroScale(t *testing.T) {
+	app := &App{HeldKeys: []Key{KEY_LEFT}}
+
+	// A binding literal without a scale works like a scale of 1
+	binding := Binding{Type: BINDING_KEY, Key: KEY_LEFT}
+	if value := binding.Value(app); value != 1 {
+		t.Errorf("value = %v, want 1", value)
+	}
+	if value := KeyBinding(KEY_LEFT).WithScale(-1).Value(app); value != -1 {
+		t.Errorf("value with scale -1 = %v, want -1", value)
+	}
+
+	data, err := json.Marshal(binding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Binding
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != KeyBinding(KEY_LEFT) {
+		t.Errorf("decoded %s as %+v, want %+v", data, decoded, KeyBinding(KEY_LEFT))
+	}
+}
+
+func TestBindingJSON(t *testing.T) {
+	bindings := []Binding{
+		KeyBinding(KEY_LEFT).WithScale(-1),
+		GamepadButtonBinding(GAMEPAD_BUTTON_A),
+		GamepadAxisBinding(GAMEPAD_AXIS_LEFTX).WithScale(0.5),
+	}
+	data, err := json.Marshal(bindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []Binding
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(bindings) {
+		t.Fatalf("decoded %d bindings, want %d", len(decoded), len(bindings))
+	}
+	for idx := range bindings {
+		if decoded[idx] != bindings[idx] {
+			t.Errorf("binding %d = %+v, want %+v", idx, decoded[idx], bindings[idx])
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"scale": 2}`), &decoded[0]); err != errInvalidBinding {
+		t.Errorf("decoding a binding without an input = %v, want errInvalidBinding", err)
+	}
+}
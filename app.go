@@ -2,6 +2,8 @@ package fine
 
 import (
 	"image/color"
+	"sync/atomic"
+	"time"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -10,11 +12,12 @@ type OnCloseFunc func() bool                                        // Function
 type UpdateFunc func(dt float64, app *App)                          // Function that is called every frame.
 type PostRenderFunc func(app *App)                                  // Function that is called at the end of drawing each frame.
 type OnKeyEventFunc func(key Key, direction KeyDirection, app *App) // Function that is called when a key is down or up. Receives the key, the direction and the app.
+type StopFunc func(app *App) bool                                   // Function that is checked before every frame. Should return whether the app should stop running or not.
 
 type App struct {
 	Title       string         // Window title.
-	Width       int32          // Window width.
-	Height      int32          // Window height.
+	Width       int32          // Render width: the logical width if it is set, otherwise the drawable width of the window.
+	Height      int32          // Render height: the logical height if it is set, otherwise the drawable height of the window.
 	Update      UpdateFunc     // Will be called every frame, receives the delta time in seconds.
 	PostRender  PostRenderFunc // Will be called after the rendering of all entities on a frame.
 	OnClose     OnCloseFunc    // Will be called when the window should be closed. Should return whether the window should close or not.
@@ -24,16 +27,88 @@ type App struct {
 	DoClear     bool           // Do clear the previous frame before drawing the new frame?
 	ClearColor  color.RGBA     // The color to clear the background of the new frame.
 	Window      *sdl.Window    // SDL window.
+	Headless    bool           // Run without a display (dummy video driver and a software renderer).
+	NullAudio   bool           // Don't open an audio device, audio is silently discarded.
+	Windows     []*App         // Additional windows opened with app.NewWindow.
+	Settings    *Settings      // Persistent settings opened with app.OpenSettings.
+	parent      *App
+
+	// Resolution.
+
+	LogicalWidth   int32     // Logical render width, 0 renders at the resolution of the window.
+	LogicalHeight  int32     // Logical render height, 0 renders at the resolution of the window.
+	ScaleMode      ScaleMode // How the logical resolution is scaled to the window. Default: SCALE_LETTERBOX.
+	WindowWidth    int32     // Window width in screen coordinates.
+	WindowHeight   int32     // Window height in screen coordinates.
+	DrawableWidth  int32     // Width of the drawable area of the window in pixels (larger than the window width on HiDPI displays).
+	DrawableHeight int32     // Height of the drawable area of the window in pixels.
+	scalingChanged bool
+
+	// Display.
+
+	Fullscreen            FullscreenMode // The fullscreen mode of the window. Default: FULLSCREEN_WINDOWED, or the mode of the WINDOW_FULLSCREEN* window flags.
+	Display               int            // The index of the display the window is created on.
+	fullscreenDisplayMode *sdl.DisplayMode
+	windowIcon            *Sprite
+	windowPosition        *sdl.Point
+	windowMinSize         sdl.Point
+	windowMaxSize         sdl.Point
+	windowOpacity         *float32
+
+	// Scenes.
+
+	Scenes          map[string]*Scene // All scenes added with app.AddScene.
+	SceneStack      []*Scene          // The scene stack, the last scene is app.Scene.
+	sceneChanges    []*sceneChange
+	sceneTransition *sceneChange
+
+	// Lifecycle.
+
+	OnStart     OnStartFunc   // Function that is called after the window and renderer are created.
+	OnStop      LifecycleFunc // Function that is called before the window and renderer are destroyed.
+	OnPause     LifecycleFunc // Function that is called when the window is minimized or loses focus.
+	OnResume    LifecycleFunc // Function that is called when the window is restored and focused again.
+	AutoPause   bool          // Pause updates and audio while the window is minimized or not focused.
+	Paused      bool          // Specifies if updates and audio are paused manually. Use app.SetPaused to change it.
+	minimized   bool
+	unfocused   bool
+	audioPaused atomic.Bool
+	pausedTime  float64 // Total time the audio was paused.
+
+	// Window events.
+
+	OnResize      OnResizeFunc    // Function that is called when the window is resized.
+	OnFocusGained WindowEventFunc // Function that is called when the window gains keyboard focus.
+	OnFocusLost   WindowEventFunc // Function that is called when the window loses keyboard focus.
+	OnMinimize    WindowEventFunc // Function that is called when the window is minimized.
+	OnRestore     WindowEventFunc // Function that is called when the window is restored.
+	OnFileDrop    OnFileDropFunc  // Function that is called when files are dropped on the window.
+	droppedFiles  []string
 
 	// Time.
 
+	Clock             Clock         // The clock used to measure time. Default: real clock.
 	Time              float64       // The amount of seconds since the app has started.
 	PreviousFrameTime float64       // The time of the previous frame.
 	DeltaTime         float64       // The duration between two frames (delta time).
+	Frame             int           // The amount of frames drawn since the app has started.
 	SwapInterval      int           // OpenGL swap interval. Default: 1 (vsync).
+	TargetFPS         float64       // Maximum frames per second, 0 means unlimited. Default: 0.
+	FrameStats        *FrameStats   // Frame time statistics of the last frames.
+	Profiler          *Profiler     // Frame profiler, nil when profiling is disabled.
 	QueuedFunctions   []*QueuedFunc // All functions that are queued to be called.
+	frameStart        time.Time
+
+	// Fixed updates.
 
-	Scene         *Scene        // The main scene of the app.
+	FixedUpdate        UpdateFunc // Will be called TickRate times per second, receives the fixed delta time in seconds.
+	TickRate           float64    // The amount of fixed updates per second. 0 disables fixed updates.
+	MaxTicksPerFrame   int        // The maximum amount of fixed updates in a single frame. Default: 5.
+	InterpolationAlpha float64    // How far the current frame is between the previous and the next fixed update (0-1).
+	accumulator        float64
+
+	Scene         *Scene        // The active scene of the app (the top of the scene stack).
+	Events        *EventBus     // Events of the app, see Subscribe and Publish.
 	Renderer      *sdl.Renderer // SDL renderer.
 	LoadedSprites []*Sprite     // All loaded sprites.
 	Camera        *Camera       // The main camera.
@@ -41,16 +116,56 @@ type App struct {
 
 	// Input.
 
-	HeldKeys             []Key          // All keys that are currently held. Call app.IsKeyDown if you want to see if a key is down.
-	JustDownKeys         []Key          // All keys that were pressed on this frame.
-	JustUpKeys           []Key          // All keys that were released on this frame.
-	OnKeyEvent           OnKeyEventFunc // Function that is called when a key is down or up.
-	DownMouseButtons     []ButtonInfo   // Pressed mouse buttons information.
-	JustDownMouseButtons []ButtonInfo   // Mouse buttons pressed on this frame information.
-	JustUpMouseButtons   []ButtonInfo   // Mouse buttons released on this frame information.
-	ScrollDeltaX         float32        // Scrollwheel X delta.
-	ScrollDeltaY         float32        // Scrollwheel Y delta.
-	IsScrolling          bool           // Is the mouse scrolling?
+	HeldKeys             []Key             // All keys that are currently held. Call app.IsKeyDown if you want to see if a key is down.
+	JustDownKeys         []Key             // All keys that were pressed on this frame.
+	JustUpKeys           []Key             // All keys that were released on this frame.
+	OnKeyEvent           OnKeyEventFunc    // Function that is called when a key is down or up.
+	OnTextInput          OnTextInputFunc   // Function that is called when text is typed.
+	OnTextEditing        OnTextEditingFunc // Function that is called when the IME composition changes.
+	DownMouseButtons     []ButtonInfo      // Pressed mouse buttons information.
+	JustDownMouseButtons []ButtonInfo      // Mouse buttons pressed on this frame information.
+	JustUpMouseButtons   []ButtonInfo      // Mouse buttons released on this frame information.
+	ScrollDeltaX         float32           // Scrollwheel X delta.
+	ScrollDeltaY         float32           // Scrollwheel Y delta.
+	IsScrolling          bool              // Is the mouse scrolling?
+	MouseX               int32             // Mouse X position, in render coordinates.
+	MouseY               int32             // Mouse Y position, in render coordinates.
+	MouseDeltaX          int32             // Mouse X movement on this frame.
+	MouseDeltaY          int32             // Mouse Y movement on this frame.
+	OnMouseMove          OnMouseMoveFunc   // Function that is called when the mouse moves.
+	Cursor               *Cursor           // The mouse cursor, nil for the default cursor.
+	CursorHidden         bool              // Specifies if the mouse cursor is hidden.
+	loadedCursors        []*Cursor
+	mouseDeltaX          float64
+	mouseDeltaY          float64
+	OnReplayEnd          ReplayEndFunc // Function that is called when an input replay has finished playing.
+	recorder             *inputRecorder
+	replayer             *inputReplayer
+
+	// Gamepads.
+
+	Gamepads              []*Gamepad       // All connected gamepads.
+	GamepadDeadzone       float64          // Default axis deadzone of new gamepads (default: 0.15).
+	OnGamepadConnected    GamepadEventFunc // Function that is called when a gamepad is connected.
+	OnGamepadDisconnected GamepadEventFunc // Function that is called when a gamepad is disconnected.
+	gamepadMappings       []string
+
+	// Touch.
+
+	Fingers         []*Finger       // All fingers that are currently touching the screen.
+	JustDownFingers []Finger        // Fingers that touched the screen on this frame.
+	JustUpFingers   []Finger        // Fingers that were lifted on this frame.
+	Gestures        []Gesture       // Gestures recognized on this frame.
+	GestureSettings GestureSettings // Gesture recognition thresholds.
+	OnGesture       OnGestureFunc   // Function that is called when a gesture is recognized.
+	hasLastTap      bool
+	lastTapTime     float64
+	lastTapX        float64
+	lastTapY        float64
+
+	// Actions.
+
+	Actions map[string]*Action // All named input actions.
 
 	// Audio.
 
@@ -61,7 +176,13 @@ type App struct {
 
 // Creates a new app with a window title and size.
 func NewApp(title string, width, height int32) *App {
-	app := &App{
+	app := newApp(title, width, height)
+	app.initAudio()
+	return app
+}
+
+func newApp(title string, width, height int32) *App {
+	return &App{
 		Title:  title,
 		Width:  width,
 		Height: height,
@@ -70,16 +191,20 @@ func NewApp(title string, width, height int32) *App {
 			WINDOW_OPENGL,
 		},
 		SwapInterval:      1,
-		Scene:             &Scene{},
+		Scene:             NewScene(),
+		Events:            NewEventBus(),
+		Actions:           make(map[string]*Action),
+		Scenes:            make(map[string]*Scene),
 		DoClear:           true,
 		Camera:            &Camera{Position: NewVec2(0, 0), Zoom: 1},
 		SampleRate:        44100,
 		ResamplingQuality: 4,
 		BufferNs:          48 * 1000000, // 48ms
+		FrameStats:        NewFrameStats(DefaultFrameStatsSize),
+		MaxTicksPerFrame:  5,
+		GamepadDeadzone:   0.15,
+		GestureSettings:   DefaultGestureSettings(),
 	}
-	app.initAudio()
-
-	return app
 }
 
 // Sets the update function (called every frame).
@@ -108,15 +233,21 @@ func (app *App) SetKeyEventFunc(newFunc OnKeyEventFunc) *App {
 
 // Sets the window width and height.
 func (app *App) SetWindowSize(width, height int32) *App {
-	app.Width, app.Height = width, height
+	if app.Window == nil {
+		// The window is created with this size
+		app.Width, app.Height = width, height
+		app.WindowWidth, app.WindowHeight = width, height
+		return app
+	}
 	app.Window.SetSize(width, height)
+	app.updateRenderSize()
 	return app
 }
 
-// Sets the window width and height.
+// Returns the window width and height in screen coordinates.
 func (app *App) GetWindowSize() (int32, int32) {
-	app.Width, app.Height = app.Window.GetSize()
-	return app.Width, app.Height
+	app.updateRenderSize()
+	return app.WindowWidth, app.WindowHeight
 }
 
 // Sets the title of the window.
@@ -139,7 +270,7 @@ func (app *App) GetFPS() float64 {
 // Sets the OpenGL swap interval.
 func (app *App) SetSwapInterval(interval int) error {
 	var err error
-	if app.Running {
+	if app.Running && !app.Headless {
 		err = sdl.GLSetSwapInterval(interval)
 	}
 	app.SwapInterval = interval
@@ -46,6 +46,7 @@ type Audio struct {
 	Silent       bool // Specifies whether the audio should be silent.
 	app          *App
 	volumeEffect *effects.Volume
+	pausedTime   float64 // The paused time of the app when the audio was last played.
 }
 
 // Loads an audio file from a ReadCloser. If the audio sample rate doesn't match
@@ -117,6 +118,9 @@ func (app *App) LoadAudioFromReader(reader io.Reader, inputFormat AudioFormat) (
 }
 
 func (app *App) initAudio() {
+	if app.NullAudio {
+		return
+	}
 	beepSampleRate := beep.SampleRate(app.SampleRate)
 
 	err := speaker.Init(beepSampleRate, beepSampleRate.N(time.Duration(app.BufferNs)*time.Nanosecond))
@@ -136,25 +140,43 @@ func (audio *Audio) GetStream() beep.Streamer {
 	return audio.volumeEffect
 }
 
-// Starts playing the audio. This is an asynchronous call.
+// Starts playing the audio. This is an asynchronous call. With null audio,
+// nothing is played, but the audio still ends after its duration.
 func (audio *Audio) Play() *Audio {
 	audio.LastPlayed = audio.app.Time
-	speaker.Play(audio.GetStream())
+	audio.pausedTime = audio.app.pausedTime
+	if audio.app.isAudioDiscarded() {
+		return audio
+	}
+	speaker.Play(pausableStreamer{Streamer: audio.GetStream(), app: audio.app})
 	return audio
 }
 
 func (audio *Audio) SetVolume(vol float64) *Audio {
 	audio.Volume = vol
-	audio.volumeEffect.Volume = vol
+	if audio.volumeEffect != nil {
+		audio.volumeEffect.Volume = vol
+	}
 	return audio
 }
 
 // Stops all playing audio.
 func (app *App) StopAudio() *App {
-	speaker.Clear()
+	if !app.isAudioDiscarded() {
+		speaker.Clear()
+	}
 	return app
 }
 
+// Checks if audio is discarded. Windows share the speaker of the app that
+// opened them, so the setting of that app is used.
+func (app *App) isAudioDiscarded() bool {
+	if app.parent != nil {
+		return app.parent.NullAudio
+	}
+	return app.NullAudio
+}
+
 // Sets the amount of nanoseconds in the audio buffer. Default: 48000000 (48ms)
 func (app *App) SetAudioBufferNs(nanoseconds int64) *App {
 	app.BufferNs = nanoseconds
@@ -174,9 +196,11 @@ func (audio *Audio) Duration() float64 {
 	return float64(audio.Buffer.Len()) / float64(audio.Buffer.Format().SampleRate)
 }
 
-// Returns if the audio has stopped playing or not.
+// Returns if the audio has stopped playing or not. The time the app audio was
+// paused is not counted.
 func (audio *Audio) Ended() bool {
-	return (audio.app.Time - audio.LastPlayed) >= audio.Duration()
+	paused := audio.app.pausedTime - audio.pausedTime
+	return (audio.app.Time - audio.LastPlayed - paused) >= audio.Duration()
 }
 
 // .mod file playback
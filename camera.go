@@ -25,3 +25,15 @@ func (camera *Camera) WorldToScreen(worldPos Vec2) (int, int) {
 	sub := worldPos.Sub(camera.Position)
 	return int(math.Round(sub.X)), int(math.Round(sub.Y))
 }
+
+// Convert screen position to world position. This is the inverse of WorldToScreen.
+func (camera *Camera) ScreenToWorld(x, y int) Vec2 {
+	return NewVec2(float64(x)+camera.Position.X, float64(y)+camera.Position.Y)
+}
+
+// Convert a screen position to a world position using the main camera,
+// taking the camera zoom and the center of the window into account.
+func (app *App) ScreenToWorld(x, y int) Vec2 {
+	pos := app.Camera.ScreenToWorld(x-int(app.Width/2), y-int(app.Height/2))
+	return NewVec2(pos.X/app.Camera.Zoom, pos.Y/app.Camera.Zoom)
+}
@@ -0,0 +1,76 @@
+package fine
+
+import "time"
+
+// Clocks are used to measure app.Time and app.DeltaTime. The app reads the
+// clock once per frame.
+type Clock interface {
+	Now() float64 // Returns the amount of seconds since the clock has started.
+}
+
+// A clock that measures the real (wall clock) time. This is the default clock.
+type RealClock struct {
+	startTime time.Time
+}
+
+// Creates a new real clock that starts counting now.
+func NewRealClock() *RealClock {
+	return &RealClock{startTime: time.Now()}
+}
+
+// Returns the amount of seconds since the clock has started.
+func (clock *RealClock) Now() float64 {
+	return time.Since(clock.startTime).Seconds()
+}
+
+// A clock that only advances when Advance or Set is called. Useful for tests
+// and replays.
+type ManualClock struct {
+	Seconds float64 // The current time of the clock in seconds.
+}
+
+// Creates a new manual clock starting at 0 seconds.
+func NewManualClock() *ManualClock {
+	return &ManualClock{}
+}
+
+// Returns the current time of the clock.
+func (clock *ManualClock) Now() float64 {
+	return clock.Seconds
+}
+
+// Advances the clock by an amount of seconds.
+func (clock *ManualClock) Advance(seconds float64) *ManualClock {
+	clock.Seconds += seconds
+	return clock
+}
+
+// Sets the current time of the clock.
+func (clock *ManualClock) Set(seconds float64) *ManualClock {
+	clock.Seconds = seconds
+	return clock
+}
+
+// A clock that advances by a fixed step every time it's read, so every frame
+// has the same delta time.
+type SteppedClock struct {
+	Step    float64 // The amount of seconds the clock advances every frame.
+	Seconds float64 // The current time of the clock in seconds.
+}
+
+// Creates a new stepped clock that advances by step seconds every frame.
+func NewSteppedClock(step float64) *SteppedClock {
+	return &SteppedClock{Step: step}
+}
+
+// Advances the clock by one step and returns the new time.
+func (clock *SteppedClock) Now() float64 {
+	clock.Seconds += clock.Step
+	return clock.Seconds
+}
+
+// Sets the clock used for app.Time and app.DeltaTime.
+func (app *App) SetClock(clock Clock) *App {
+	app.Clock = clock
+	return app
+}
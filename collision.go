@@ -13,14 +13,14 @@ type CollisionInfo struct {
 // This feature is still experimental. Suitable for platformer games.
 func (entity *Entity) Collide() CollisionInfo {
 	// Check collisions with other entities
-	pos1 := entity.Position
+	pos1 := entity.WorldPosition()
 	collision := CollisionInfo{}
 
 	for _, ent := range entity.Scene.Entities {
 		if ent == entity || !ent.DoCollide {
 			continue
 		}
-		pos2 := ent.Position
+		pos2 := ent.WorldPosition()
 
 		// Find collisions
 		if !(pos1.X+entity.Width < pos2.X ||
@@ -50,5 +50,9 @@ func (entity *Entity) Collide() CollisionInfo {
 
 // Checks if a rect is on the screen (should be drawn) or not.
 func (app *App) isRectOnScreen(x, y, w, h int32) bool {
-	return !(x+w < 0 || y+h < 0 || x > app.Width+w || y > app.Height+h)
+	if x+w < 0 || y+h < 0 || x > app.Width+w || y > app.Height+h {
+		app.Profiler.countCulled()
+		return false
+	}
+	return true
 }
@@ -0,0 +1,156 @@
+package fine
+
+// A reusable behavior attached to an entity. Components are updated and drawn
+// in the order they were added, after the entity update function. Embed
+// BaseComponent to only implement the methods you need.
+type Component interface {
+	Start(app *App, entity *Entity)              // Called before the first update of the component.
+	Update(dt float64, app *App, entity *Entity) // Called every frame while the scene of the entity is active.
+	Draw(app *App, entity *Entity)               // Called after the entity is drawn, to draw on top of it.
+	OnDestroy(app *App, entity *Entity)          // Called when a started component is removed or its entity is destroyed.
+}
+
+// Empty implementations of all component methods.
+type BaseComponent struct{}
+
+func (BaseComponent) Start(app *App, entity *Entity)              {}
+func (BaseComponent) Update(dt float64, app *App, entity *Entity) {}
+func (BaseComponent) Draw(app *App, entity *Entity)               {}
+func (BaseComponent) OnDestroy(app *App, entity *Entity)          {}
+
+// A component attached to an entity, returned by entity.AddComponent. It is
+// used to remove the component, since components don't have to be comparable.
+type AttachedComponent struct {
+	component Component
+	entity    *Entity
+	started   bool
+}
+
+// Attaches a component to the entity. It is started before its first update.
+func (entity *Entity) AddComponent(component Component) *AttachedComponent {
+	attached := &AttachedComponent{component: component, entity: entity}
+	entity.components = append(entity.components, attached)
+	return attached
+}
+
+// Returns the attached component.
+func (attached *AttachedComponent) Component() Component {
+	return attached.component
+}
+
+// Returns the entity the component is attached to.
+func (attached *AttachedComponent) Entity() *Entity {
+	return attached.entity
+}
+
+// Removes the component from its entity, see entity.RemoveComponent.
+func (attached *AttachedComponent) Remove() bool {
+	return attached.entity.RemoveComponent(attached)
+}
+
+// Removes a component from the entity and calls its OnDestroy method if it was
+// started. Returns false if the component isn't attached to the entity.
+func (entity *Entity) RemoveComponent(attached *AttachedComponent) bool {
+	for idx, other := range entity.components {
+		if other == attached {
+			entity.components = append(entity.components[:idx], entity.components[idx+1:]...)
+			attached.destroy()
+			return true
+		}
+	}
+	return false
+}
+
+// Calls OnDestroy, only if the component was started.
+func (attached *AttachedComponent) destroy() {
+	if attached.started {
+		attached.component.OnDestroy(attached.entity.app, attached.entity)
+	}
+}
+
+// Returns all components of the entity, in the order they were added.
+func (entity *Entity) GetComponents() []Component {
+	components := make([]Component, len(entity.components))
+	for idx, attached := range entity.components {
+		components[idx] = attached.component
+	}
+	return components
+}
+
+// Returns the first component of the entity with type T. T can also be an
+// interface, in which case the first component implementing it is returned.
+func GetComponent[T any](entity *Entity) (T, bool) {
+	for _, attached := range entity.components {
+		if component, ok := attached.component.(T); ok {
+			return component, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Returns all components of the entity with type T.
+func GetComponents[T any](entity *Entity) []T {
+	var components []T
+	for _, attached := range entity.components {
+		if component, ok := attached.component.(T); ok {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// Checks if the entity has a component with type T.
+func HasComponent[T any](entity *Entity) bool {
+	_, ok := GetComponent[T](entity)
+	return ok
+}
+
+// Starts and updates all components of the entity.
+func (entity *Entity) updateComponents(dt float64, app *App) {
+	if len(entity.components) == 0 {
+		return
+	}
+	// Components can be added and removed while updating
+	for _, attached := range append([]*AttachedComponent(nil), entity.components...) {
+		if entity.destroyed {
+			return
+		}
+		if !entity.hasAttached(attached) {
+			continue
+		}
+		if !attached.started {
+			attached.started = true
+			attached.component.Start(app, entity)
+		}
+		attached.component.Update(dt, app, entity)
+	}
+}
+
+func (entity *Entity) hasAttached(attached *AttachedComponent) bool {
+	for _, other := range entity.components {
+		if other == attached {
+			return true
+		}
+	}
+	return false
+}
+
+// Draws all components of the entity on top of it.
+func (entity *Entity) drawComponents(app *App) {
+	if len(entity.components) == 0 || !entity.WorldTransform().Visible {
+		return
+	}
+	for _, attached := range entity.components {
+		attached.component.Draw(app, entity)
+	}
+}
+
+// Calls OnDestroy of all components when the entity is destroyed.
+func (entity *Entity) destroyComponents() {
+	components := entity.components
+	entity.components = nil
+	for _, attached := range components {
+		attached.destroy()
+	}
+}
@@ -0,0 +1,141 @@
+package fine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Records its lifecycle calls as "name:method".
+type recordingComponent struct {
+	BaseComponent
+	name     string
+	calls    *[]string
+	onUpdate func(entity *Entity)
+}
+
+func (component *recordingComponent) Start(app *App, entity *Entity) {
+	*component.calls = append(*component.calls, component.name+":start")
+}
+
+func (component *recordingComponent) Update(dt float64, app *App, entity *Entity) {
+	*component.calls = append(*component.calls, component.name+":update")
+	if component.onUpdate != nil {
+		component.onUpdate(entity)
+	}
+}
+
+func (component *recordingComponent) Draw(app *App, entity *Entity) {
+	*component.calls = append(*component.calls, component.name+":draw")
+}
+
+func (component *recordingComponent) OnDestroy(app *App, entity *Entity) {
+	*component.calls = append(*component.calls, component.name+":destroy")
+}
+
+type otherComponent struct {
+	BaseComponent
+}
+
+func TestComponentLifecycle(t *testing.T) {
+	var calls []string
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	first := &recordingComponent{name: "first", calls: &calls}
+	second := &recordingComponent{name: "second", calls: &calls}
+	entity.AddComponent(first)
+	attached := entity.AddComponent(second)
+
+	entity.updateComponents(0.1, nil)
+	entity.updateComponents(0.1, nil)
+	entity.drawComponents(nil)
+	if !attached.Remove() || attached.Remove() {
+		t.Error("Remove should only succeed once")
+	}
+	entity.Destroy()
+
+	want := []string{
+		"first:start", "first:update", "second:start", "second:update",
+		"first:update", "second:update",
+		"first:draw", "second:draw",
+		"second:destroy", "first:destroy",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestComponentNotStarted(t *testing.T) {
+	var calls []string
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	entity.AddComponent(&recordingComponent{name: "removed", calls: &calls}).Remove()
+	entity.AddComponent(&recordingComponent{name: "destroyed", calls: &calls})
+	entity.Destroy()
+
+	// OnDestroy is only called for started components
+	if len(calls) != 0 {
+		t.Errorf("calls = %v, want none", calls)
+	}
+}
+
+func TestComponentsChangedWhileUpdating(t *testing.T) {
+	var calls []string
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	added := &recordingComponent{name: "added", calls: &calls}
+	var removed *AttachedComponent
+	entity.AddComponent(&recordingComponent{name: "first", calls: &calls, onUpdate: func(entity *Entity) {
+		if removed.Remove() {
+			entity.AddComponent(added)
+		}
+	}})
+	removed = entity.AddComponent(&recordingComponent{name: "removed", calls: &calls})
+
+	// The removed component is skipped and the added one starts on the next update
+	entity.updateComponents(0.1, nil)
+	entity.updateComponents(0.1, nil)
+	want := []string{"first:start", "first:update", "first:update", "added:start", "added:update"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestComponentDestroysEntity(t *testing.T) {
+	var calls []string
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	entity.AddComponent(&recordingComponent{name: "first", calls: &calls, onUpdate: func(entity *Entity) {
+		entity.Destroy()
+	}})
+	entity.AddComponent(&recordingComponent{name: "second", calls: &calls})
+
+	entity.updateComponents(0.1, nil)
+	want := []string{"first:start", "first:update", "first:destroy"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestGetComponent(t *testing.T) {
+	var calls []string
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	first := &recordingComponent{name: "first", calls: &calls}
+	entity.AddComponent(first)
+	entity.AddComponent(&otherComponent{})
+	entity.AddComponent(&recordingComponent{name: "second", calls: &calls})
+
+	if component, ok := GetComponent[*recordingComponent](entity); !ok || component != first {
+		t.Errorf("GetComponent = %v, %v, want the first component", component, ok)
+	}
+	if count := len(GetComponents[*recordingComponent](entity)); count != 2 {
+		t.Errorf("found %d recording components, want 2", count)
+	}
+	if count := len(GetComponents[Component](entity)); count != 3 {
+		t.Errorf("found %d components, want 3", count)
+	}
+	if HasComponent[*BaseComponent](entity) {
+		t.Error("HasComponent found a component that wasn't added")
+	}
+
+	entity.Visible = false
+	entity.drawComponents(nil)
+	if len(calls) != 0 {
+		t.Errorf("hidden entity drew its components: %v", calls)
+	}
+}
@@ -0,0 +1,146 @@
+package fine
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// System mouse cursors.
+type SystemCursor int
+
+const (
+	CURSOR_ARROW     SystemCursor = sdl.SYSTEM_CURSOR_ARROW     // Arrow.
+	CURSOR_IBEAM     SystemCursor = sdl.SYSTEM_CURSOR_IBEAM     // I-beam (text selection).
+	CURSOR_WAIT      SystemCursor = sdl.SYSTEM_CURSOR_WAIT      // Wait.
+	CURSOR_CROSSHAIR SystemCursor = sdl.SYSTEM_CURSOR_CROSSHAIR // Crosshair.
+	CURSOR_WAITARROW SystemCursor = sdl.SYSTEM_CURSOR_WAITARROW // Small wait cursor (or wait if not available).
+	CURSOR_SIZENWSE  SystemCursor = sdl.SYSTEM_CURSOR_SIZENWSE  // Double arrow pointing northwest and southeast.
+	CURSOR_SIZENESW  SystemCursor = sdl.SYSTEM_CURSOR_SIZENESW  // Double arrow pointing northeast and southwest.
+	CURSOR_SIZEWE    SystemCursor = sdl.SYSTEM_CURSOR_SIZEWE    // Double arrow pointing west and east (horizontal resize).
+	CURSOR_SIZENS    SystemCursor = sdl.SYSTEM_CURSOR_SIZENS    // Double arrow pointing north and south (vertical resize).
+	CURSOR_SIZEALL   SystemCursor = sdl.SYSTEM_CURSOR_SIZEALL   // Four pointed arrow (move).
+	CURSOR_NO        SystemCursor = sdl.SYSTEM_CURSOR_NO        // Slashed circle or crossbones.
+	CURSOR_HAND      SystemCursor = sdl.SYSTEM_CURSOR_HAND      // Hand (link).
+)
+
+// A mouse cursor, either a system cursor or a sprite. The SDL cursor is
+// created when the cursor is first used after app.Run has been called.
+type Cursor struct {
+	System SystemCursor // The system cursor, used if Sprite is nil.
+	Sprite *Sprite      // The sprite of the cursor.
+	HotX   int32        // X position of the click point in the sprite.
+	HotY   int32        // Y position of the click point in the sprite.
+	Cursor *sdl.Cursor  // SDL cursor.
+}
+
+// Creates a new system cursor.
+func NewSystemCursor(id SystemCursor) *Cursor {
+	return &Cursor{System: id}
+}
+
+// Creates a new cursor from a sprite. The hotspot is the position of the
+// click point in the sprite.
+func NewSpriteCursor(sprite *Sprite, hotX, hotY int32) *Cursor {
+	return &Cursor{Sprite: sprite, HotX: hotX, HotY: hotY}
+}
+
+// Creates the SDL cursor if it wasn't created yet.
+func (cursor *Cursor) load() error {
+	if cursor.Cursor != nil {
+		return nil
+	}
+
+	if cursor.Sprite != nil {
+		if cursor.Sprite.Surface == nil {
+			return fmt.Errorf("sprite has no surface, cannot create cursor")
+		}
+		cursor.Cursor = sdl.CreateColorCursor(cursor.Sprite.Surface, cursor.HotX, cursor.HotY)
+	} else {
+		cursor.Cursor = sdl.CreateSystemCursor(sdl.SystemCursor(cursor.System))
+	}
+
+	if cursor.Cursor == nil {
+		return sdl.GetError()
+	}
+	return nil
+}
+
+// Frees the SDL cursor. The cursor can still be used, it will be created again.
+func (cursor *Cursor) Free() {
+	if cursor == nil || cursor.Cursor == nil {
+		return
+	}
+	sdl.FreeCursor(cursor.Cursor)
+	cursor.Cursor = nil
+}
+
+// Sets the mouse cursor. Passing nil restores the default cursor. If the app
+// is not running yet, the cursor is set when it starts.
+func (app *App) SetCursor(cursor *Cursor) error {
+	app.Cursor = cursor
+	if !app.Running {
+		return nil
+	}
+	return app.applyCursor()
+}
+
+// Sets the mouse cursor to a system cursor.
+func (app *App) SetSystemCursor(id SystemCursor) error {
+	return app.SetCursor(NewSystemCursor(id))
+}
+
+// Sets the mouse cursor to a sprite with a hotspot.
+func (app *App) SetSpriteCursor(sprite *Sprite, hotX, hotY int32) error {
+	return app.SetCursor(NewSpriteCursor(sprite, hotX, hotY))
+}
+
+func (app *App) applyCursor() error {
+	if app.Headless {
+		// The dummy video driver can't create cursors
+		return nil
+	}
+	if app.Cursor == nil {
+		sdl.SetCursor(sdl.GetDefaultCursor())
+		return nil
+	}
+	if app.Cursor.Cursor == nil {
+		if err := app.Cursor.load(); err != nil {
+			return err
+		}
+		app.loadedCursors = append(app.loadedCursors, app.Cursor)
+	}
+	sdl.SetCursor(app.Cursor.Cursor)
+	return nil
+}
+
+// Frees all SDL cursors created by the app.
+func (app *App) freeCursors() {
+	for _, cursor := range app.loadedCursors {
+		cursor.Free()
+	}
+	app.loadedCursors = nil
+}
+
+// Shows the mouse cursor.
+func (app *App) ShowCursor() *App {
+	app.CursorHidden = false
+	if app.Running {
+		sdl.ShowCursor(sdl.ENABLE)
+	}
+	return app
+}
+
+// Hides the mouse cursor.
+func (app *App) HideCursor() *App {
+	app.CursorHidden = true
+	if app.Running {
+		sdl.ShowCursor(sdl.DISABLE)
+	}
+	return app
+}
+
+// Checks if the mouse cursor is visible.
+func (app *App) IsCursorVisible() bool {
+	return !app.CursorHidden
+}
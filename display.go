@@ -0,0 +1,385 @@
+package fine
+
+import (
+	"errors"
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	errNoIconImage error = errors.New("the icon sprite has no surface")
+)
+
+type FullscreenMode int
+
+const (
+	FULLSCREEN_WINDOWED  FullscreenMode = iota // Not fullscreen.
+	FULLSCREEN_DESKTOP                         // Borderless window covering the display, at the desktop resolution.
+	FULLSCREEN_EXCLUSIVE                       // Exclusive fullscreen, changes the display mode (see app.SetFullscreenDisplayMode).
+)
+
+// Returns the SDL window flags of the fullscreen mode.
+func (mode FullscreenMode) flags() uint32 {
+	switch mode {
+	case FULLSCREEN_DESKTOP:
+		return sdl.WINDOW_FULLSCREEN_DESKTOP
+	case FULLSCREEN_EXCLUSIVE:
+		return sdl.WINDOW_FULLSCREEN
+	}
+	return 0
+}
+
+// A resolution and refresh rate supported by a display.
+type DisplayMode struct {
+	Width       int32  // Width in screen coordinates.
+	Height      int32  // Height in screen coordinates.
+	RefreshRate int32  // Refresh rate in Hz, 0 if unknown.
+	Format      uint32 // SDL pixel format.
+}
+
+// A connected display (monitor).
+type Display struct {
+	Index        int           // The index of the display.
+	Name         string        // The name of the display.
+	Bounds       Rect          // The position and size of the display on the desktop.
+	UsableBounds Rect          // The bounds without the taskbar, dock and similar.
+	DPI          float64       // Diagonal DPI of the display, 0 if unknown.
+	DesktopMode  DisplayMode   // The current desktop display mode.
+	Modes        []DisplayMode // All supported display modes, from the largest to the smallest.
+}
+
+// A rectangle on the desktop.
+type Rect struct {
+	X, Y int32
+	W, H int32
+}
+
+func newDisplayMode(mode sdl.DisplayMode) DisplayMode {
+	return DisplayMode{Width: mode.W, Height: mode.H, RefreshRate: mode.RefreshRate, Format: mode.Format}
+}
+
+func newRect(rect sdl.Rect) Rect {
+	return Rect{X: rect.X, Y: rect.Y, W: rect.W, H: rect.H}
+}
+
+// Calls fn with the SDL video subsystem initialized. If the app is not running,
+// the subsystem is only initialized until fn returns.
+func withVideo(fn func() error) error {
+	if sdl.WasInit(sdl.INIT_VIDEO) == 0 {
+		if err := sdl.InitSubSystem(sdl.INIT_VIDEO); err != nil {
+			return err
+		}
+		defer sdl.QuitSubSystem(sdl.INIT_VIDEO)
+	}
+	return fn()
+}
+
+// Returns all connected displays. This can be called before the app is running.
+func GetDisplays() ([]Display, error) {
+	var displays []Display
+	err := withVideo(func() error {
+		count, err := sdl.GetNumVideoDisplays()
+		if err != nil {
+			return err
+		}
+
+		displays = make([]Display, 0, count)
+		for idx := 0; idx < count; idx++ {
+			display, err := getDisplay(idx)
+			if err != nil {
+				return err
+			}
+			displays = append(displays, display)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return displays, nil
+}
+
+// Returns information about a display and its supported modes. This can be
+// called before the app is running.
+func GetDisplay(index int) (Display, error) {
+	var display Display
+	err := withVideo(func() error {
+		var err error
+		display, err = getDisplay(index)
+		return err
+	})
+	return display, err
+}
+
+func getDisplay(index int) (Display, error) {
+	display := Display{Index: index}
+
+	var err error
+	if display.Name, err = sdl.GetDisplayName(index); err != nil {
+		return display, err
+	}
+	bounds, err := sdl.GetDisplayBounds(index)
+	if err != nil {
+		return display, err
+	}
+	display.Bounds = newRect(bounds)
+
+	if usable, err := sdl.GetDisplayUsableBounds(index); err == nil {
+		display.UsableBounds = newRect(usable)
+	} else {
+		display.UsableBounds = display.Bounds
+	}
+	if ddpi, _, _, err := sdl.GetDisplayDPI(index); err == nil {
+		display.DPI = float64(ddpi)
+	}
+
+	desktop, err := sdl.GetDesktopDisplayMode(index)
+	if err != nil {
+		return display, err
+	}
+	display.DesktopMode = newDisplayMode(desktop)
+
+	count, err := sdl.GetNumDisplayModes(index)
+	if err != nil {
+		return display, err
+	}
+	for modeIdx := 0; modeIdx < count; modeIdx++ {
+		mode, err := sdl.GetDisplayMode(index, modeIdx)
+		if err != nil {
+			return display, err
+		}
+		display.Modes = append(display.Modes, newDisplayMode(mode))
+	}
+	return display, nil
+}
+
+// Sets the fullscreen mode of the window. If the window is not created yet,
+// it will be created with this mode.
+func (app *App) SetFullscreen(mode FullscreenMode) error {
+	if app.Window != nil {
+		if mode == FULLSCREEN_EXCLUSIVE && app.fullscreenDisplayMode != nil {
+			if err := app.Window.SetDisplayMode(app.fullscreenDisplayMode); err != nil {
+				return err
+			}
+		}
+		if err := app.Window.SetFullscreen(mode.flags()); err != nil {
+			return err
+		}
+	}
+
+	app.Fullscreen = mode
+	app.removeFullscreenFlags()
+	if app.Window != nil {
+		app.updateRenderSize()
+	}
+	return nil
+}
+
+// Returns the fullscreen mode of SDL window flags.
+func fullscreenModeOf(flags uint32) FullscreenMode {
+	switch {
+	case flags&sdl.WINDOW_FULLSCREEN_DESKTOP == sdl.WINDOW_FULLSCREEN_DESKTOP:
+		return FULLSCREEN_DESKTOP
+	case flags&sdl.WINDOW_FULLSCREEN != 0:
+		return FULLSCREEN_EXCLUSIVE
+	}
+	return FULLSCREEN_WINDOWED
+}
+
+// Removes the fullscreen window flags, app.Fullscreen is used instead.
+func (app *App) removeFullscreenFlags() {
+	// Don't modify the slice in place, it may belong to the caller of
+	// app.SetWindowFlags
+	var flags []WindowFlag
+	for _, flag := range app.WindowFlags {
+		if flag != WINDOW_FULLSCREEN && flag != WINDOW_FULLSCREEN_DESKTOP {
+			flags = append(flags, flag)
+		}
+	}
+	if len(flags) == 0 {
+		// The window can't be created without flags
+		flags = append(flags, WINDOW_SHOWN)
+	}
+	app.WindowFlags = flags
+}
+
+// Switches between windowed and borderless desktop fullscreen.
+func (app *App) ToggleFullscreen() error {
+	if app.Fullscreen == FULLSCREEN_WINDOWED {
+		return app.SetFullscreen(FULLSCREEN_DESKTOP)
+	}
+	return app.SetFullscreen(FULLSCREEN_WINDOWED)
+}
+
+// Checks if the window is fullscreen.
+func (app *App) IsFullscreen() bool {
+	return app.Fullscreen != FULLSCREEN_WINDOWED
+}
+
+// Sets the display mode used in exclusive fullscreen. The closest mode
+// supported by the display is used. Without a display mode, SDL uses the mode
+// closest to the window size.
+func (app *App) SetFullscreenDisplayMode(mode DisplayMode) error {
+	app.fullscreenDisplayMode = &sdl.DisplayMode{
+		Format:      mode.Format,
+		W:           mode.Width,
+		H:           mode.Height,
+		RefreshRate: mode.RefreshRate,
+	}
+	if app.Window == nil || app.Fullscreen != FULLSCREEN_EXCLUSIVE {
+		return nil
+	}
+	return app.Window.SetDisplayMode(app.fullscreenDisplayMode)
+}
+
+// Moves the window to the center of a display. If the window is not created
+// yet, it will be created on this display.
+func (app *App) SetWindowDisplay(index int) error {
+	app.Display = index
+	app.windowPosition = nil
+	if app.Window == nil {
+		return nil
+	}
+
+	// Leave fullscreen while moving, otherwise the window stays on the old display
+	fullscreen := app.Fullscreen
+	if fullscreen != FULLSCREEN_WINDOWED {
+		if err := app.SetFullscreen(FULLSCREEN_WINDOWED); err != nil {
+			return err
+		}
+	}
+	position := int32(sdl.WINDOWPOS_CENTERED_MASK | index)
+	app.Window.SetPosition(position, position)
+	if fullscreen != FULLSCREEN_WINDOWED {
+		return app.SetFullscreen(fullscreen)
+	}
+	return nil
+}
+
+// Returns the index of the display the window is on.
+func (app *App) GetWindowDisplay() (int, error) {
+	if app.Window == nil {
+		return app.Display, nil
+	}
+	return app.Window.GetDisplayIndex()
+}
+
+// Sets the window icon to the image of a sprite. If the window is not created
+// yet, the icon is set when it is created.
+func (app *App) SetWindowIcon(sprite *Sprite) error {
+	if sprite == nil || sprite.Surface == nil {
+		return errNoIconImage
+	}
+	app.windowIcon = sprite
+	if app.Window != nil {
+		app.Window.SetIcon(sprite.Surface)
+	}
+	return nil
+}
+
+// Sets the position of the window on the desktop. If the window is not
+// created yet, it will be created at this position.
+func (app *App) SetWindowPosition(x, y int32) error {
+	app.windowPosition = &sdl.Point{X: x, Y: y}
+	if app.Window != nil {
+		app.Window.SetPosition(x, y)
+	}
+	return nil
+}
+
+// Returns the position of the window on the desktop. If the window is not
+// created yet, returns the position set with app.SetWindowPosition, or 0, 0.
+func (app *App) GetWindowPosition() (int32, int32) {
+	if app.Window == nil {
+		if app.windowPosition != nil {
+			return app.windowPosition.X, app.windowPosition.Y
+		}
+		return 0, 0
+	}
+	return app.Window.GetPosition()
+}
+
+// Moves the window to the center of the display it is on.
+func (app *App) CenterWindow() error {
+	display, err := app.GetWindowDisplay()
+	if err != nil {
+		return err
+	}
+	return app.SetWindowDisplay(display)
+}
+
+// Sets the minimum size of the window, 0 means no limit. If the window is not
+// created yet, the size is set when it is created.
+func (app *App) SetWindowMinSize(width, height int32) error {
+	app.windowMinSize = sdl.Point{X: width, Y: height}
+	if app.Window != nil {
+		app.Window.SetMinimumSize(width, height)
+	}
+	return nil
+}
+
+// Returns the minimum size of the window.
+func (app *App) GetWindowMinSize() (int32, int32) {
+	if app.Window == nil {
+		return app.windowMinSize.X, app.windowMinSize.Y
+	}
+	return app.Window.GetMinimumSize()
+}
+
+// Sets the maximum size of the window, 0 means no limit. If the window is not
+// created yet, the size is set when it is created.
+func (app *App) SetWindowMaxSize(width, height int32) error {
+	app.windowMaxSize = sdl.Point{X: width, Y: height}
+	if app.Window != nil {
+		app.Window.SetMaximumSize(width, height)
+	}
+	return nil
+}
+
+// Returns the maximum size of the window.
+func (app *App) GetWindowMaxSize() (int32, int32) {
+	if app.Window == nil {
+		return app.windowMaxSize.X, app.windowMaxSize.Y
+	}
+	return app.Window.GetMaximumSize()
+}
+
+// Sets the opacity of the window (0-1). Not supported on every platform. If
+// the window is not created yet, the opacity is set when it is created.
+func (app *App) SetWindowOpacity(opacity float32) error {
+	app.windowOpacity = &opacity
+	if app.Window == nil {
+		return nil
+	}
+	return app.Window.SetWindowOpacity(opacity)
+}
+
+// Returns the opacity of the window (0-1).
+func (app *App) GetWindowOpacity() (float32, error) {
+	if app.Window == nil {
+		if app.windowOpacity != nil {
+			return *app.windowOpacity, nil
+		}
+		return 1, nil
+	}
+	return app.Window.GetWindowOpacity()
+}
+
+// Applies the window settings that were set before the window was created.
+func (app *App) applyWindowSettings() {
+	if app.windowIcon != nil && app.windowIcon.Surface != nil {
+		app.Window.SetIcon(app.windowIcon.Surface)
+	}
+	if app.windowMinSize.X > 0 || app.windowMinSize.Y > 0 {
+		app.Window.SetMinimumSize(app.windowMinSize.X, app.windowMinSize.Y)
+	}
+	if app.windowMaxSize.X > 0 || app.windowMaxSize.Y > 0 {
+		app.Window.SetMaximumSize(app.windowMaxSize.X, app.windowMaxSize.Y)
+	}
+	if app.windowOpacity != nil {
+		if err := app.Window.SetWindowOpacity(*app.windowOpacity); err != nil {
+			log.Printf("[warn] failed to set the window opacity: %s", err)
+		}
+	}
+}
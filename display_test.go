@@ -0,0 +1,43 @@
+package fine
+
+import "testing"
+
+func TestWindowSettingsBeforeRun(t *testing.T) {
+	app := &App{}
+	if err := app.SetWindowPosition(30, 40); err != nil {
+		t.Errorf("SetWindowPosition: %s", err)
+	}
+	if err := app.SetWindowMinSize(320, 240); err != nil {
+		t.Errorf("SetWindowMinSize: %s", err)
+	}
+	if err := app.SetWindowMaxSize(1920, 0); err != nil {
+		t.Errorf("SetWindowMaxSize: %s", err)
+	}
+	if opacity, err := app.GetWindowOpacity(); opacity != 1 || err != nil {
+		t.Errorf("default opacity = %v, %v, want 1", opacity, err)
+	}
+	if err := app.SetWindowOpacity(0.5); err != nil {
+		t.Errorf("SetWindowOpacity: %s", err)
+	}
+
+	if x, y := app.GetWindowPosition(); x != 30 || y != 40 {
+		t.Errorf("position = %d, %d, want 30, 40", x, y)
+	}
+	if w, h := app.GetWindowMinSize(); w != 320 || h != 240 {
+		t.Errorf("min size = %d, %d, want 320, 240", w, h)
+	}
+	if w, h := app.GetWindowMaxSize(); w != 1920 || h != 0 {
+		t.Errorf("max size = %d, %d, want 1920, 0", w, h)
+	}
+	if opacity, err := app.GetWindowOpacity(); opacity != 0.5 || err != nil {
+		t.Errorf("opacity = %v, %v, want 0.5", opacity, err)
+	}
+
+	// Moving the window to a display replaces the position
+	if err := app.SetWindowDisplay(1); err != nil {
+		t.Fatal(err)
+	}
+	if x, y := app.GetWindowPosition(); x != 0 || y != 0 || app.Display != 1 {
+		t.Errorf("position = %d, %d on display %d, want 0, 0 on display 1", x, y, app.Display)
+	}
+}
@@ -2,8 +2,16 @@ package fine
 
 // Draws a new frame.
 func (app *App) DrawFrame() error {
-	if app.Update != nil {
-		app.Update(app.DeltaTime, app)
+	updateStart := app.Profiler.begin()
+	app.updateScenes()
+	app.updateActions()
+	app.updateGestures()
+	if !app.IsPaused() {
+		app.fixedUpdate()
+
+		if app.Update != nil {
+			app.Update(app.DeltaTime, app)
+		}
 	}
 
 	// Clear the slices of just up/down keys
@@ -11,7 +19,13 @@ func (app *App) DrawFrame() error {
 	app.JustDownMouseButtons = nil
 	app.JustUpKeys = nil
 	app.JustUpMouseButtons = nil
+	app.clearJustGamepadButtons()
+	app.JustDownFingers = nil
+	app.JustUpFingers = nil
+	app.Gestures = nil
+	app.Profiler.end(PROFILE_UPDATE, updateStart)
 
+	drawStart := app.Profiler.begin()
 	if app.DoClear {
 		prevR, prevG, prevB, prevA, err := app.Renderer.GetDrawColor()
 		if err != nil {
@@ -21,57 +35,112 @@ func (app *App) DrawFrame() error {
 		app.Renderer.Clear()
 		app.Renderer.SetDrawColor(prevR, prevG, prevB, prevA)
 	}
+	app.Profiler.end(PROFILE_DRAW, drawStart)
 
-	// TODO: Proper layer system
-	// Draw entities
-	for _, entity := range app.Scene.Entities {
-		// Follow parent (TODO: Rotate around parent)
-		if entity.Parent != nil {
-			entity.Position.X += entity.Parent.positionDelta.X
-			entity.Position.Y += entity.Parent.positionDelta.Y
-		}
-
-		if entity.UpdateFunc != nil {
-			entity.UpdateFunc(app.DeltaTime, app, entity)
-		}
-		if err := app.DrawEntity(entity); err != nil {
+	// Draw the paused scenes visible under the active scene, then the active scene
+	for _, scene := range app.visiblePausedScenes() {
+		if err := app.drawScene(scene, false); err != nil {
 			return err
 		}
-
-		if entity.previousPosition.X != entity.Position.X || entity.previousPosition.Y != entity.Position.Y {
-			entity.positionDelta = entity.Position.Sub(entity.previousPosition)
-			entity.previousPosition = entity.Position
-		}
+	}
+	if err := app.drawScene(app.Scene, !app.IsPaused()); err != nil {
+		return err
 	}
 
 	// Check queued functions
-	for idx, cronFunc := range app.QueuedFunctions {
-		cronFunc.Left -= app.DeltaTime
+	updateStart = app.Profiler.begin()
+	if !app.IsPaused() {
+		for idx, cronFunc := range app.QueuedFunctions {
+			cronFunc.Left -= app.DeltaTime
 
-		if cronFunc.Left <= 0 && !cronFunc.shouldRepeat {
-			if cronFunc.Func != nil {
+			if cronFunc.Left <= 0 && !cronFunc.shouldRepeat {
+				if cronFunc.Func != nil {
+					cronFunc.Func(app)
+				}
+				app.QueuedFunctions = append(app.QueuedFunctions[:idx], app.QueuedFunctions[idx+1:]...)
+			} else if cronFunc.Left <= 0 && cronFunc.shouldRepeat {
 				cronFunc.Func(app)
+				// Start counting down again
+				cronFunc.Left = cronFunc.startTime
 			}
-			app.QueuedFunctions = append(app.QueuedFunctions[:idx], app.QueuedFunctions[idx+1:]...)
-		} else if cronFunc.Left <= 0 && cronFunc.shouldRepeat {
-			cronFunc.Func(app)
-			// Start counting down again
-			cronFunc.Left = cronFunc.startTime
 		}
 	}
 
+	// Publish the events queued on this frame, paused scenes included
+	app.Events.Flush()
+	for _, scene := range append([]*Scene(nil), app.sceneStack()...) {
+		scene.Events.Flush()
+	}
+	app.Profiler.end(PROFILE_UPDATE, updateStart)
+
+	drawStart = app.Profiler.begin()
 	if app.PostRender != nil {
 		app.PostRender(app)
 	}
+	app.drawSceneTransition()
+	app.Profiler.end(PROFILE_DRAW, drawStart)
 
 	return nil
 }
 
-// Blits an entity to the screen.
+// Updates (if update is true) and draws all entities of a scene in layer order.
+func (app *App) drawScene(scene *Scene, update bool) error {
+	// Iterate over a copy, so entities can be added and destroyed while updating
+	for _, entity := range scene.sortedEntities() {
+		if entity.destroyed {
+			continue
+		}
+		entityUpdateStart := app.Profiler.begin()
+		if update {
+			if entity.UpdateFunc != nil {
+				entity.UpdateFunc(app.DeltaTime, app, entity)
+			}
+			entity.updateComponents(app.DeltaTime, app)
+		}
+		app.Profiler.end(PROFILE_ENTITY_UPDATE, entityUpdateStart)
+		if entity.destroyed {
+			continue
+		}
+
+		entityDrawStart := app.Profiler.begin()
+		if err := app.drawLayered(entity); err != nil {
+			return err
+		}
+		app.Profiler.end(PROFILE_DRAW, entityDrawStart)
+	}
+	return nil
+}
+
+// Draws an entity according to its layer.
+func (app *App) drawLayered(entity *Entity) error {
+	if layer := entity.Layer; layer != nil {
+		if !layer.Visible {
+			return nil
+		}
+		if layer.ScreenSpace {
+			camera := app.Camera
+			screenCamera := screenSpaceCamera
+			app.Camera = &screenCamera
+			defer func() { app.Camera = camera }()
+		}
+	}
+
+	if err := app.DrawEntity(entity); err != nil {
+		return err
+	}
+	entity.drawComponents(app)
+	return nil
+}
+
+// Blits an entity to the screen, using its world transform. Entities that
+// interpolate are drawn at their interpolated position and angle.
 func (app *App) DrawEntity(entity *Entity) error {
-	if !entity.Visible || entity.Opacity == 0 {
+	world := entity.worldTransform(true)
+	if !world.Visible || world.Opacity == 0 {
 		return nil
 	}
+	defer entity.restoreTransform(entity.swapTransform(world))
+	defer app.Profiler.countDrawn(app.Profiler.culledCount())
 
 	// Draw textures
 	if entity.Texture != nil {
@@ -3,7 +3,9 @@ package fine
 import "image/color"
 
 type Entity struct {
-	Position        Vec2             // The world position of the entity.
+	Name            string           // The name of the entity, see Scene.FindByName.
+	Tags            []string         // The tags of the entity, see Scene.FindAllByTag.
+	Position        Vec2             // The world position of the entity, or the position relative to the parent.
 	Scene           *Scene           // The scene this entity is in.
 	Texture         *Sprite          // The texture (sprite) of the entity.
 	Shape           Shape            // The shape of the entity. This is ignored if a texture is set.
@@ -19,11 +21,20 @@ type Entity struct {
 	Height          float64          // Height of the texture or shape.
 	DoCollide       bool             // Can this entity collide with other entities?
 	UpdateFunc      EntityUpdateFunc // This function will be called before drawing the entity.
-	Parent          *Entity          // The parent of this entity.
-
-	app              *App
-	previousPosition Vec2
-	positionDelta    Vec2
+	FixedUpdateFunc EntityUpdateFunc // This function will be called on every fixed update.
+	Interpolate     bool             // Draw the entity between its previous and current fixed update position.
+	Parent          *Entity          // The parent of this entity. Use ParentTo to change it.
+	Children        []*Entity        // The children of this entity.
+	Layer           *Layer           // The render layer of the entity, nil for the default layer.
+	ZIndex          int              // Draw order in the layer, entities with a higher Z index are drawn on top.
+
+	app           *App
+	tickPosition  Vec2
+	tickAngle     float64
+	ticked        bool
+	subscriptions []*Subscription
+	destroyed     bool
+	components    []*AttachedComponent
 }
 
 type FlipDirection int
@@ -32,6 +43,7 @@ const (
 	FLIP_NONE       FlipDirection = 0
 	FLIP_HORIZONTAL FlipDirection = 1
 	FLIP_VERTICAL   FlipDirection = 2
+	FLIP_BOTH       FlipDirection = FLIP_HORIZONTAL | FLIP_VERTICAL
 )
 
 type EntityUpdateFunc func(dt float64, app *App, entity *Entity)
@@ -45,22 +57,37 @@ type Shape interface {
 // Creates a new entity on the scene.
 func (app *App) Entity(position Vec2) *Entity {
 	entity := &Entity{
-		Position:         position,
-		Scene:            app.Scene,
-		Scale:            NewVec2(1, 1),
-		Visible:          true,
-		Opacity:          1,
-		DoCollide:        true,
-		app:              app,
-		previousPosition: position,
+		Position:  position,
+		Scene:     app.Scene,
+		Scale:     NewVec2(1, 1),
+		Visible:   true,
+		Opacity:   1,
+		DoCollide: true,
+		app:       app,
 	}
 
 	app.Scene.Entities = append(app.Scene.Entities, entity)
 	return entity
 }
 
-// Removes this entity from the scene. This does not free the sprite
+// Removes this entity from the scene, its parent and its event subscriptions.
+// The children are unparented and stay in the scene. This does not free the sprite
 func (entity *Entity) Destroy() {
+	entity.destroyed = true
+	entity.destroyComponents()
+	entity.unsubscribeAll()
+	for len(entity.Children) > 0 {
+		entity.Children[0].RemoveParent()
+	}
+	entity.detachFromParent()
+	entity.removeFromScene()
+}
+
+// Removes the entity from the entities of its scene.
+func (entity *Entity) removeFromScene() {
+	if entity.Scene == nil {
+		return
+	}
 	for idx, sceneEntity := range entity.Scene.Entities {
 		if sceneEntity == entity {
 			entity.Scene.Entities = append(entity.Scene.Entities[:idx], entity.Scene.Entities[idx+1:]...)
@@ -143,21 +170,10 @@ func (entity *Entity) SetFlip(flip FlipDirection) *Entity {
 	return entity
 }
 
-// "Toggles" a flip direction of the entity.
+// "Toggles" a flip direction of the entity. The directions are flags, so
+// toggling FLIP_HORIZONTAL on a vertically flipped entity gives FLIP_BOTH.
 func (entity *Entity) FlipToggle(flip FlipDirection) *Entity {
-	if flip == FLIP_HORIZONTAL {
-		if entity.FlipDir == FLIP_NONE {
-			entity.FlipDir = FLIP_HORIZONTAL
-		} else if entity.FlipDir == FLIP_HORIZONTAL {
-			entity.FlipDir = FLIP_NONE
-		}
-	} else if flip == FLIP_VERTICAL {
-		if entity.FlipDir == FLIP_NONE {
-			entity.FlipDir = FLIP_VERTICAL
-		} else if entity.FlipDir == FLIP_VERTICAL {
-			entity.FlipDir = FLIP_NONE
-		}
-	}
+	entity.FlipDir ^= flip
 	return entity
 }
 
@@ -173,15 +189,29 @@ func (entity *Entity) SetUpdateFunc(updateFunc EntityUpdateFunc) *Entity {
 	return entity
 }
 
-// Parents this entity to an another entity. Children will follow the parent's
-// position.
+// Parents this entity to an another entity. The entity keeps its place in the
+// world, and from then on its position, angle, scale and flip are relative to
+// the parent. It also inherits the opacity and visibility of the parent.
+// Parenting an entity to itself or one of its children is ignored.
 func (entity *Entity) ParentTo(parent *Entity) *Entity {
+	if parent == nil {
+		return entity.RemoveParent()
+	}
+	if parent == entity || parent.isDescendantOf(entity) {
+		return entity
+	}
+	world := entity.WorldTransform()
+	entity.detachFromParent()
 	entity.Parent = parent
+	parent.Children = append(parent.Children, entity)
+	entity.setWorldTransform(world)
 	return entity
 }
 
-// Unparents this entity.
+// Unparents this entity. The entity keeps its place in the world.
 func (entity *Entity) RemoveParent() *Entity {
-	entity.Parent = nil
+	world := entity.WorldTransform()
+	entity.detachFromParent()
+	entity.setWorldTransform(world)
 	return entity
 }
@@ -0,0 +1,159 @@
+package fine
+
+import "reflect"
+
+// Delivers typed events to subscribers. Events are matched by their type, so
+// any type can be used as an event (usually a struct). Use the Subscribe,
+// Publish and Queue functions to use the bus.
+type EventBus struct {
+	handlers map[reflect.Type][]*Subscription
+	queue    []func()
+}
+
+// A handle to a subscribed event handler.
+type Subscription struct {
+	Entity    *Entity // The entity that owns the subscription, if any. It is unsubscribed when the entity is destroyed.
+	bus       *EventBus
+	eventType reflect.Type
+	handler   any
+	active    bool
+}
+
+// Creates a new event bus.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[reflect.Type][]*Subscription)}
+}
+
+func eventTypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Calls handler every time an event of type T is published on the bus.
+func Subscribe[T any](bus *EventBus, handler func(event T)) *Subscription {
+	subscription := &Subscription{
+		bus:       bus,
+		eventType: eventTypeOf[T](),
+		handler:   handler,
+		active:    true,
+	}
+	if bus.handlers == nil {
+		bus.handlers = make(map[reflect.Type][]*Subscription)
+	}
+	bus.handlers[subscription.eventType] = append(bus.handlers[subscription.eventType], subscription)
+	return subscription
+}
+
+// Subscribes to events of type T on behalf of an entity. The subscription is
+// removed automatically when the entity is destroyed.
+func SubscribeEntity[T any](bus *EventBus, entity *Entity, handler func(event T, entity *Entity)) *Subscription {
+	subscription := Subscribe(bus, func(event T) {
+		handler(event, entity)
+	})
+	subscription.Entity = entity
+	entity.subscriptions = append(entity.subscriptions, subscription)
+	return subscription
+}
+
+// Calls all handlers subscribed to events of type T immediately.
+func Publish[T any](bus *EventBus, event T) {
+	// Copy the handlers, so handlers can subscribe and unsubscribe while the
+	// event is being delivered
+	subscriptions := append([]*Subscription(nil), bus.handlers[eventTypeOf[T]()]...)
+	for _, subscription := range subscriptions {
+		if subscription.active {
+			subscription.handler.(func(T))(event)
+		}
+	}
+}
+
+// Queues an event, it will be published at the end of the frame (after the
+// entities are updated and drawn, before the post render function). The app
+// bus and the buses of the scenes on the scene stack are flushed every frame,
+// events queued on other buses wait until the scene is pushed or bus.Flush is
+// called.
+func Queue[T any](bus *EventBus, event T) {
+	bus.queue = append(bus.queue, func() {
+		Publish(bus, event)
+	})
+}
+
+// Publishes all queued events. Events queued by the handlers are published on
+// the next flush.
+func (bus *EventBus) Flush() {
+	if bus == nil {
+		return
+	}
+	queue := bus.queue
+	bus.queue = nil
+	for _, publish := range queue {
+		publish()
+	}
+}
+
+// Removes all subscriptions and queued events.
+func (bus *EventBus) Clear() {
+	for _, subscriptions := range bus.handlers {
+		for _, subscription := range subscriptions {
+			subscription.active = false
+			subscription.detachEntity()
+		}
+	}
+	bus.handlers = make(map[reflect.Type][]*Subscription)
+	bus.queue = nil
+}
+
+// Returns the amount of handlers subscribed to events of type T.
+func SubscriberCount[T any](bus *EventBus) int {
+	return len(bus.handlers[eventTypeOf[T]()])
+}
+
+// Removes the subscription, the handler won't be called anymore.
+func (subscription *Subscription) Unsubscribe() {
+	// The subscription may already be inactive after bus.Clear, but it still
+	// has to be removed from its entity
+	subscription.detachEntity()
+	if !subscription.active {
+		return
+	}
+	subscription.active = false
+
+	subscriptions := subscription.bus.handlers[subscription.eventType]
+	for idx, other := range subscriptions {
+		if other == subscription {
+			subscriptions = append(subscriptions[:idx:idx], subscriptions[idx+1:]...)
+			break
+		}
+	}
+	if len(subscriptions) == 0 {
+		delete(subscription.bus.handlers, subscription.eventType)
+	} else {
+		subscription.bus.handlers[subscription.eventType] = subscriptions
+	}
+}
+
+// Removes the subscription from the subscriptions of its entity.
+func (subscription *Subscription) detachEntity() {
+	entity := subscription.Entity
+	if entity == nil {
+		return
+	}
+	for idx, other := range entity.subscriptions {
+		if other == subscription {
+			entity.subscriptions = append(entity.subscriptions[:idx], entity.subscriptions[idx+1:]...)
+			break
+		}
+	}
+}
+
+// Checks if the handler is still subscribed.
+func (subscription *Subscription) IsActive() bool {
+	return subscription.active
+}
+
+// Removes all subscriptions of the entity.
+func (entity *Entity) unsubscribeAll() {
+	for _, subscription := range append([]*Subscription(nil), entity.subscriptions...) {
+		subscription.Unsubscribe()
+	}
+	entity.subscriptions = nil
+}
@@ -0,0 +1,159 @@
+package fine
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testEvent struct {
+	Value int
+}
+
+type otherTestEvent struct{}
+
+func TestPublishOrder(t *testing.T) {
+	bus := NewEventBus()
+	var calls []string
+	Subscribe(bus, func(event testEvent) {
+		calls = append(calls, "first")
+	})
+	second := Subscribe(bus, func(event testEvent) {
+		calls = append(calls, "second")
+	})
+	Subscribe(bus, func(event testEvent) {
+		calls = append(calls, "third")
+		// Handlers subscribed while publishing get the next event
+		Subscribe(bus, func(event testEvent) {
+			calls = append(calls, "late")
+		})
+		second.Unsubscribe()
+	})
+	Subscribe(bus, func(event otherTestEvent) {
+		calls = append(calls, "other")
+	})
+
+	Publish(bus, testEvent{})
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	Publish(bus, testEvent{})
+	if want := []string{"first", "third", "late"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if second.IsActive() || SubscriberCount[testEvent](bus) != 4 {
+		t.Errorf("second active: %v, %d subscribers, want inactive, 4", second.IsActive(), SubscriberCount[testEvent](bus))
+	}
+}
+
+func TestUnsubscribeDuringPublish(t *testing.T) {
+	bus := NewEventBus()
+	var calls []string
+	var second *Subscription
+	Subscribe(bus, func(event testEvent) {
+		calls = append(calls, "first")
+		second.Unsubscribe()
+	})
+	second = Subscribe(bus, func(event testEvent) {
+		calls = append(calls, "second")
+	})
+
+	// The second handler is removed before its turn
+	Publish(bus, testEvent{})
+	if want := []string{"first"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestQueueAndFlush(t *testing.T) {
+	bus := NewEventBus()
+	var values []int
+	Subscribe(bus, func(event testEvent) {
+		values = append(values, event.Value)
+		if event.Value == 1 {
+			Queue(bus, testEvent{Value: 3})
+		}
+	})
+
+	Queue(bus, testEvent{Value: 1})
+	Queue(bus, testEvent{Value: 2})
+	if len(values) != 0 {
+		t.Fatalf("queued events were published before flushing: %v", values)
+	}
+
+	// Events queued by the handlers wait for the next flush
+	bus.Flush()
+	if want := []int{1, 2}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	bus.Flush()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+
+	Queue(bus, testEvent{Value: 4})
+	bus.Clear()
+	bus.Flush()
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values after Clear = %v, want %v", values, want)
+	}
+}
+
+func TestEntitySubscriptions(t *testing.T) {
+	bus := NewEventBus()
+	entity := newTestEntity(NewVec2(0, 0), 1, 1)
+	calls := 0
+	subscription := SubscribeEntity(bus, entity, func(event testEvent, entity *Entity) {
+		calls++
+	})
+
+	Publish(bus, testEvent{})
+	entity.Destroy()
+	Publish(bus, testEvent{})
+	if calls != 1 || subscription.IsActive() || SubscriberCount[testEvent](bus) != 0 {
+		t.Errorf("calls = %d, active: %v, want 1 call and the subscription removed", calls, subscription.IsActive())
+	}
+}
+
+func TestSceneGetEvents(t *testing.T) {
+	scene := &Scene{}
+	called := false
+	Subscribe(scene.GetEvents(), func(event testEvent) {
+		called = true
+	})
+	Publish(scene.Events, testEvent{})
+	if !called {
+		t.Error("handler on the scene bus was not called")
+	}
+	if scene.GetEvents() != scene.Events {
+		t.Error("GetEvents created a second bus")
+	}
+}
+
+func TestPausedSceneEventsHeadless(t *testing.T) {
+	app := NewHeadlessApp("events", 32, 32)
+	base := app.Scene
+	app.AddScene("pause")
+
+	var delivered []int
+	Subscribe(base.Events, func(event testEvent) {
+		delivered = append(delivered, app.Frame)
+	})
+	app.SetUpdateFunc(func(dt float64, app *App) {
+		if app.Frame == 1 {
+			app.PushScene("pause", nil)
+		}
+		if app.Frame == 3 {
+			Queue(base.Events, testEvent{})
+		}
+	})
+
+	if err := app.RunFrames(5); err != nil {
+		t.Fatalf("RunFrames: %s", err)
+	}
+	// The base scene is paused, but its queued event is published on the same frame
+	if app.Scene == base || !reflect.DeepEqual(delivered, []int{3}) {
+		t.Errorf("events delivered on frames %v, want [3]", delivered)
+	}
+}
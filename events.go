@@ -0,0 +1,109 @@
+package fine
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type OnResizeFunc func(width, height int32, app *App) // Function that is called when the window is resized. Receives the new window size and the app.
+type WindowEventFunc func(app *App)                   // Function that is called when the window state changes (focus, minimize, restore).
+type OnFileDropFunc func(paths []string, app *App)    // Function that is called when files are dropped on the window. Receives the paths of the files and the app.
+
+func (app *App) handleWindowEvent(event *sdl.WindowEvent) {
+	switch event.Event {
+	case sdl.WINDOWEVENT_SIZE_CHANGED:
+		app.updateRenderSize()
+		if app.OnResize != nil {
+			app.OnResize(event.Data1, event.Data2, app)
+		}
+	case sdl.WINDOWEVENT_FOCUS_GAINED:
+		if app.OnFocusGained != nil {
+			app.OnFocusGained(app)
+		}
+		app.setInactive(app.minimized, false)
+	case sdl.WINDOWEVENT_FOCUS_LOST:
+		if app.OnFocusLost != nil {
+			app.OnFocusLost(app)
+		}
+		app.setInactive(app.minimized, true)
+	case sdl.WINDOWEVENT_MINIMIZED:
+		if app.OnMinimize != nil {
+			app.OnMinimize(app)
+		}
+		app.setInactive(true, app.unfocused)
+	case sdl.WINDOWEVENT_RESTORED:
+		if app.OnRestore != nil {
+			app.OnRestore(app)
+		}
+		app.setInactive(false, app.unfocused)
+	case sdl.WINDOWEVENT_CLOSE:
+		// SDL only sends a quit event when the last window is closed, so
+		// additional windows and the main window with other windows open
+		// have to be closed here
+		if app.parent != nil || len(app.Windows) > 0 {
+			app.requestClose()
+		}
+	}
+}
+
+func (app *App) handleDropEvent(event *sdl.DropEvent) {
+	switch event.Type {
+	case sdl.DROPBEGIN:
+		app.droppedFiles = nil
+	case sdl.DROPFILE:
+		app.droppedFiles = append(app.droppedFiles, event.File)
+	case sdl.DROPCOMPLETE:
+		app.flushDroppedFiles()
+	}
+}
+
+// Calls the file drop function with all files dropped since the last call.
+// SDL versions before 2.0.5 don't send DROPCOMPLETE, so this is also called
+// after all events of a frame are handled.
+func (app *App) flushDroppedFiles() {
+	if len(app.droppedFiles) == 0 {
+		return
+	}
+	paths := app.droppedFiles
+	app.droppedFiles = nil
+
+	if app.OnFileDrop != nil {
+		app.OnFileDrop(paths, app)
+	}
+}
+
+// Sets the resize function (called when the window size changes).
+func (app *App) SetResizeFunc(newFunc OnResizeFunc) *App {
+	app.OnResize = newFunc
+	return app
+}
+
+// Sets the focus gained function (called when the window gains keyboard focus).
+func (app *App) SetFocusGainedFunc(newFunc WindowEventFunc) *App {
+	app.OnFocusGained = newFunc
+	return app
+}
+
+// Sets the focus lost function (called when the window loses keyboard focus).
+func (app *App) SetFocusLostFunc(newFunc WindowEventFunc) *App {
+	app.OnFocusLost = newFunc
+	return app
+}
+
+// Sets the minimize function (called when the window is minimized).
+func (app *App) SetMinimizeFunc(newFunc WindowEventFunc) *App {
+	app.OnMinimize = newFunc
+	return app
+}
+
+// Sets the restore function (called when the window is restored after being
+// minimized or maximized).
+func (app *App) SetRestoreFunc(newFunc WindowEventFunc) *App {
+	app.OnRestore = newFunc
+	return app
+}
+
+// Sets the file drop function (called when files are dropped on the window).
+func (app *App) SetFileDropFunc(newFunc OnFileDropFunc) *App {
+	app.OnFileDrop = newFunc
+	return app
+}
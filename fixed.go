@@ -0,0 +1,103 @@
+package fine
+
+import "math"
+
+// Runs the fixed updates for the time that has passed since the previous frame
+// and calculates the interpolation alpha.
+func (app *App) fixedUpdate() {
+	if app.TickRate <= 0 {
+		app.InterpolationAlpha = 1
+		return
+	}
+	step := 1 / app.TickRate
+	app.accumulator += app.DeltaTime
+
+	for ticks := 0; app.accumulator >= step; ticks++ {
+		if app.MaxTicksPerFrame > 0 && ticks >= app.MaxTicksPerFrame {
+			// We can't catch up, drop the time we're behind
+			app.accumulator = math.Mod(app.accumulator, step)
+			break
+		}
+		app.tick(step)
+		app.accumulator -= step
+	}
+
+	app.InterpolationAlpha = app.accumulator / step
+}
+
+// Runs a single fixed update.
+func (app *App) tick(step float64) {
+	for _, entity := range app.Scene.Entities {
+		entity.tickPosition = entity.Position
+		entity.tickAngle = entity.Angle
+		entity.ticked = true
+	}
+
+	if app.FixedUpdate != nil {
+		app.FixedUpdate(step, app)
+	}
+	// Iterate over a copy, so entities can be added and destroyed while updating
+	entities := append([]*Entity(nil), app.Scene.Entities...)
+	for _, entity := range entities {
+		if entity.destroyed {
+			continue
+		}
+		if entity.FixedUpdateFunc != nil {
+			entity.FixedUpdateFunc(step, app, entity)
+		}
+	}
+}
+
+// Returns the position of the entity between its previous and current fixed
+// update, using app.InterpolationAlpha.
+func (entity *Entity) GetInterpolatedPosition() Vec2 {
+	if !entity.ticked {
+		return entity.Position
+	}
+	alpha := entity.app.InterpolationAlpha
+	return NewVec2(
+		entity.tickPosition.X+(entity.Position.X-entity.tickPosition.X)*alpha,
+		entity.tickPosition.Y+(entity.Position.Y-entity.tickPosition.Y)*alpha,
+	)
+}
+
+// Returns the angle of the entity between its previous and current fixed
+// update, using app.InterpolationAlpha.
+func (entity *Entity) GetInterpolatedAngle() float64 {
+	if !entity.ticked {
+		return entity.Angle
+	}
+	return entity.tickAngle + (entity.Angle-entity.tickAngle)*entity.app.InterpolationAlpha
+}
+
+// Sets the fixed update function (called TickRate times per second).
+func (app *App) SetFixedUpdateFunc(newFunc UpdateFunc) *App {
+	app.FixedUpdate = newFunc
+	return app
+}
+
+// Sets the amount of fixed updates per second. 0 disables fixed updates.
+func (app *App) SetTickRate(rate float64) *App {
+	app.TickRate = rate
+	return app
+}
+
+// Sets the maximum amount of fixed updates that can run in a single frame.
+func (app *App) SetMaxTicksPerFrame(ticks int) *App {
+	app.MaxTicksPerFrame = ticks
+	return app
+}
+
+// Sets the fixed update function of the entity. It will be called
+// app.TickRate times per second.
+func (entity *Entity) SetFixedUpdateFunc(updateFunc EntityUpdateFunc) *Entity {
+	entity.FixedUpdateFunc = updateFunc
+	return entity
+}
+
+// Specifies whether the entity should be drawn between its previous and
+// current fixed update position.
+func (entity *Entity) SetInterpolate(state bool) *Entity {
+	entity.Interpolate = state
+	return entity
+}
@@ -0,0 +1,53 @@
+package fine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFixedUpdateSteps(t *testing.T) {
+	app := &App{Scene: NewScene(), TickRate: 10, MaxTicksPerFrame: 5}
+	ticks := 0
+	app.FixedUpdate = func(dt float64, app *App) {
+		ticks++
+	}
+
+	app.DeltaTime = 0.25
+	app.fixedUpdate()
+	if ticks != 2 || math.Abs(app.InterpolationAlpha-0.5) > 1e-9 {
+		t.Errorf("ticks = %d, alpha = %v, want 2, 0.5", ticks, app.InterpolationAlpha)
+	}
+
+	// Long frames are capped at MaxTicksPerFrame
+	ticks = 0
+	app.DeltaTime = 2
+	app.fixedUpdate()
+	if ticks != 5 {
+		t.Errorf("ticks = %d, want 5", ticks)
+	}
+}
+
+func TestTickDestroyedEntities(t *testing.T) {
+	app := &App{Scene: NewScene()}
+	calls := map[string]int{}
+	update := func(dt float64, app *App, entity *Entity) {
+		calls[entity.Name]++
+	}
+
+	first := newTestEntity(NewVec2(0, 0), 1, 1).SetName("first")
+	second := newTestEntity(NewVec2(0, 0), 1, 1).SetName("second")
+	third := newTestEntity(NewVec2(0, 0), 1, 1).SetName("third")
+	for _, entity := range []*Entity{first, second, third} {
+		entity.FixedUpdateFunc = update
+		app.Scene.Add(entity)
+	}
+	first.FixedUpdateFunc = func(dt float64, app *App, entity *Entity) {
+		update(dt, app, entity)
+		second.Destroy()
+	}
+
+	app.tick(0.1)
+	if calls["first"] != 1 || calls["second"] != 0 || calls["third"] != 1 {
+		t.Errorf("fixed update calls = %v, want first: 1, second: 0, third: 1", calls)
+	}
+}
@@ -0,0 +1,439 @@
+package fine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type GamepadEventFunc func(gamepad *Gamepad, app *App) // Function that is called when a gamepad is connected or disconnected. Receives the gamepad and the app.
+
+// A connected game controller. The gamepad state is updated from SDL controller
+// events, so it can also be driven with events pushed with sdl.PushEvent.
+type Gamepad struct {
+	ID              int                         // Joystick instance ID of the gamepad.
+	Name            string                      // Name of the gamepad.
+	Controller      *sdl.GameController         // SDL game controller, nil for gamepads connected by an input replay.
+	HeldButtons     []GamepadButton             // All buttons that are currently held.
+	JustDownButtons []GamepadButton             // All buttons that were pressed on this frame.
+	JustUpButtons   []GamepadButton             // All buttons that were released on this frame.
+	Deadzones       [GAMEPAD_AXIS_COUNT]float64 // Deadzone of every axis between 0 and 1. Axis values inside the deadzone are 0.
+	axes            [GAMEPAD_AXIS_COUNT]float64
+}
+
+// Game controller buttons.
+type GamepadButton int
+
+// Game controller axes.
+type GamepadAxis int
+
+const (
+	GAMEPAD_BUTTON_A             GamepadButton = sdl.CONTROLLER_BUTTON_A             // Bottom face button (A on Xbox controllers).
+	GAMEPAD_BUTTON_B             GamepadButton = sdl.CONTROLLER_BUTTON_B             // Right face button (B on Xbox controllers).
+	GAMEPAD_BUTTON_X             GamepadButton = sdl.CONTROLLER_BUTTON_X             // Left face button (X on Xbox controllers).
+	GAMEPAD_BUTTON_Y             GamepadButton = sdl.CONTROLLER_BUTTON_Y             // Top face button (Y on Xbox controllers).
+	GAMEPAD_BUTTON_BACK          GamepadButton = sdl.CONTROLLER_BUTTON_BACK          // Back (select) button.
+	GAMEPAD_BUTTON_GUIDE         GamepadButton = sdl.CONTROLLER_BUTTON_GUIDE         // Guide (home) button.
+	GAMEPAD_BUTTON_START         GamepadButton = sdl.CONTROLLER_BUTTON_START         // Start button.
+	GAMEPAD_BUTTON_LEFTSTICK     GamepadButton = sdl.CONTROLLER_BUTTON_LEFTSTICK     // Left stick press.
+	GAMEPAD_BUTTON_RIGHTSTICK    GamepadButton = sdl.CONTROLLER_BUTTON_RIGHTSTICK    // Right stick press.
+	GAMEPAD_BUTTON_LEFTSHOULDER  GamepadButton = sdl.CONTROLLER_BUTTON_LEFTSHOULDER  // Left shoulder button.
+	GAMEPAD_BUTTON_RIGHTSHOULDER GamepadButton = sdl.CONTROLLER_BUTTON_RIGHTSHOULDER // Right shoulder button.
+	GAMEPAD_BUTTON_DPAD_UP       GamepadButton = sdl.CONTROLLER_BUTTON_DPAD_UP       // D-pad up.
+	GAMEPAD_BUTTON_DPAD_DOWN     GamepadButton = sdl.CONTROLLER_BUTTON_DPAD_DOWN     // D-pad down.
+	GAMEPAD_BUTTON_DPAD_LEFT     GamepadButton = sdl.CONTROLLER_BUTTON_DPAD_LEFT     // D-pad left.
+	GAMEPAD_BUTTON_DPAD_RIGHT    GamepadButton = sdl.CONTROLLER_BUTTON_DPAD_RIGHT    // D-pad right.
+
+	GAMEPAD_AXIS_LEFTX        GamepadAxis = sdl.CONTROLLER_AXIS_LEFTX        // Left stick X axis.
+	GAMEPAD_AXIS_LEFTY        GamepadAxis = sdl.CONTROLLER_AXIS_LEFTY        // Left stick Y axis.
+	GAMEPAD_AXIS_RIGHTX       GamepadAxis = sdl.CONTROLLER_AXIS_RIGHTX       // Right stick X axis.
+	GAMEPAD_AXIS_RIGHTY       GamepadAxis = sdl.CONTROLLER_AXIS_RIGHTY       // Right stick Y axis.
+	GAMEPAD_AXIS_TRIGGERLEFT  GamepadAxis = sdl.CONTROLLER_AXIS_TRIGGERLEFT  // Left trigger.
+	GAMEPAD_AXIS_TRIGGERRIGHT GamepadAxis = sdl.CONTROLLER_AXIS_TRIGGERRIGHT // Right trigger.
+
+	GAMEPAD_AXIS_COUNT = 6 // The amount of gamepad axes.
+)
+
+func (app *App) handleControllerDeviceEvent(event *sdl.ControllerDeviceEvent) {
+	switch event.Type {
+	case sdl.CONTROLLERDEVICEADDED:
+		// For added devices, Which is the device index
+		controller := sdl.GameControllerOpen(int(event.Which))
+		if controller == nil {
+			return
+		}
+		id := int(controller.Joystick().InstanceID())
+		if app.GetGamepad(id) != nil {
+			// SDL sends added events for the gamepads that were already connected
+			controller.Close()
+			return
+		}
+
+		app.addGamepad(&Gamepad{
+			ID:         id,
+			Name:       controller.Name(),
+			Controller: controller,
+		})
+	case sdl.CONTROLLERDEVICEREMOVED:
+		for idx, gamepad := range app.Gamepads {
+			if gamepad.ID == int(event.Which) {
+				app.Gamepads = append(app.Gamepads[:idx], app.Gamepads[idx+1:]...)
+				gamepad.close()
+
+				if app.OnGamepadDisconnected != nil {
+					app.OnGamepadDisconnected(gamepad, app)
+				}
+				break
+			}
+		}
+	}
+}
+
+// Adds a connected gamepad and calls the gamepad connected function.
+func (app *App) addGamepad(gamepad *Gamepad) {
+	for axis := range gamepad.Deadzones {
+		gamepad.Deadzones[axis] = app.GamepadDeadzone
+	}
+	app.Gamepads = append(app.Gamepads, gamepad)
+
+	if app.OnGamepadConnected != nil {
+		app.OnGamepadConnected(gamepad, app)
+	}
+}
+
+// Adds a gamepad connected in an input replay. It has no SDL controller, its
+// state only comes from the replayed events.
+func (app *App) addReplayedGamepad(id int) {
+	if app.GetGamepad(id) != nil {
+		return
+	}
+	app.addGamepad(&Gamepad{ID: id})
+}
+
+// Closes the SDL controller of the gamepad, if it has one.
+func (gamepad *Gamepad) close() {
+	if gamepad.Controller != nil {
+		gamepad.Controller.Close()
+	}
+}
+
+func (app *App) handleControllerButtonEvent(event *sdl.ControllerButtonEvent) {
+	gamepad := app.GetGamepad(int(event.Which))
+	if gamepad == nil {
+		return
+	}
+	button := GamepadButton(event.Button)
+	isDown := gamepad.IsButtonDown(button)
+
+	if event.State == sdl.PRESSED && !isDown {
+		gamepad.HeldButtons = append(gamepad.HeldButtons, button)
+		gamepad.JustDownButtons = append(gamepad.JustDownButtons, button)
+	} else if event.State == sdl.RELEASED && isDown {
+		gamepad.JustUpButtons = append(gamepad.JustUpButtons, button)
+		for idx, heldButton := range gamepad.HeldButtons {
+			if button == heldButton {
+				gamepad.HeldButtons = append(gamepad.HeldButtons[:idx], gamepad.HeldButtons[idx+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (app *App) handleControllerAxisEvent(event *sdl.ControllerAxisEvent) {
+	gamepad := app.GetGamepad(int(event.Which))
+	if gamepad == nil || int(event.Axis) >= GAMEPAD_AXIS_COUNT {
+		return
+	}
+	gamepad.axes[event.Axis] = math.Max(float64(event.Value)/32767, -1)
+}
+
+// Clears the buttons that were pressed or released on this frame.
+func (app *App) clearJustGamepadButtons() {
+	for _, gamepad := range app.Gamepads {
+		gamepad.JustDownButtons = nil
+		gamepad.JustUpButtons = nil
+	}
+}
+
+// Closes all connected gamepads.
+func (app *App) closeGamepads() {
+	for _, gamepad := range app.Gamepads {
+		gamepad.close()
+	}
+	app.Gamepads = nil
+}
+
+// Returns the gamepad with a joystick instance ID, or nil if it's not connected.
+func (app *App) GetGamepad(id int) *Gamepad {
+	for _, gamepad := range app.Gamepads {
+		if gamepad.ID == id {
+			return gamepad
+		}
+	}
+	return nil
+}
+
+// Checks if a button is currently pressed on any gamepad.
+func (app *App) IsGamepadButtonDown(button GamepadButton) bool {
+	for _, gamepad := range app.Gamepads {
+		if gamepad.IsButtonDown(button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a button was pressed on this frame on any gamepad.
+func (app *App) IsGamepadButtonJustDown(button GamepadButton) bool {
+	for _, gamepad := range app.Gamepads {
+		if gamepad.IsButtonJustDown(button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a button was released on this frame on any gamepad.
+func (app *App) IsGamepadButtonJustUp(button GamepadButton) bool {
+	for _, gamepad := range app.Gamepads {
+		if gamepad.IsButtonJustUp(button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sets the deadzone used for gamepads that connect after this call.
+func (app *App) SetGamepadDeadzone(deadzone float64) *App {
+	app.GamepadDeadzone = deadzone
+	return app
+}
+
+// Sets the gamepad connected function (called when a gamepad is connected).
+func (app *App) SetGamepadConnectedFunc(newFunc GamepadEventFunc) *App {
+	app.OnGamepadConnected = newFunc
+	return app
+}
+
+// Sets the gamepad disconnected function (called when a gamepad is disconnected).
+func (app *App) SetGamepadDisconnectedFunc(newFunc GamepadEventFunc) *App {
+	app.OnGamepadDisconnected = newFunc
+	return app
+}
+
+// Loads gamepad mappings in the SDL_GameControllerDB format (one mapping per
+// line, lines starting with # are ignored). Like SDL, only the mappings with a
+// "platform:" field matching the current platform are used. If the app is not
+// running yet, the mappings are added when it starts. Mappings that SDL can't
+// parse are skipped with a warning. Returns the amount of loaded mappings.
+func (app *App) LoadGamepadMappings(reader io.Reader) (int, error) {
+	mappings, err := readGamepadMappings(reader, sdl.GetPlatform())
+	if err != nil {
+		return 0, err
+	}
+
+	if !app.Running {
+		app.gamepadMappings = append(app.gamepadMappings, mappings...)
+		return len(mappings), nil
+	}
+	return addGamepadMappings(mappings), nil
+}
+
+// Reads the mappings for a platform from a mapping file.
+func readGamepadMappings(reader io.Reader, platform string) ([]string, error) {
+	var mappings []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Other platforms can have mappings for the same GUID, which would
+		// replace the mapping of this platform
+		if mappingPlatform(line) != platform {
+			continue
+		}
+		mappings = append(mappings, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// Returns the value of the "platform:" field of a mapping, or "" if it has none.
+func mappingPlatform(mapping string) string {
+	for _, field := range strings.Split(mapping, ",") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "platform:") {
+			return strings.TrimSpace(strings.TrimPrefix(field, "platform:"))
+		}
+	}
+	return ""
+}
+
+// Loads gamepad mappings from a file, see app.LoadGamepadMappings.
+func (app *App) LoadGamepadMappingsFromFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return app.LoadGamepadMappings(file)
+}
+
+// Adds gamepad mappings to SDL. A broken mapping shouldn't stop the app, so
+// mappings that can't be added are skipped with a warning.
+func addGamepadMappings(mappings []string) int {
+	loaded := 0
+	for _, mapping := range mappings {
+		if sdl.GameControllerAddMapping(mapping) < 0 {
+			log.Printf("[warn] failed to add gamepad mapping %q: %s", mapping, sdl.GetError())
+			continue
+		}
+		loaded++
+	}
+	return loaded
+}
+
+// Checks if a button is currently pressed.
+func (gamepad *Gamepad) IsButtonDown(button GamepadButton) bool {
+	for _, heldButton := range gamepad.HeldButtons {
+		if button == heldButton {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a button was pressed on this frame.
+func (gamepad *Gamepad) IsButtonJustDown(button GamepadButton) bool {
+	for _, downButton := range gamepad.JustDownButtons {
+		if button == downButton {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a button was released on this frame.
+func (gamepad *Gamepad) IsButtonJustUp(button GamepadButton) bool {
+	for _, upButton := range gamepad.JustUpButtons {
+		if button == upButton {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the value of an axis between -1 and 1 (triggers are between 0 and 1)
+// with the deadzone applied.
+func (gamepad *Gamepad) GetAxis(axis GamepadAxis) float64 {
+	if axis < 0 || int(axis) >= GAMEPAD_AXIS_COUNT {
+		return 0
+	}
+	value, deadzone := gamepad.axes[axis], gamepad.Deadzones[axis]
+	if math.Abs(value) <= deadzone {
+		return 0
+	}
+	// Rescale the value so it starts at 0 outside the deadzone
+	return math.Copysign((math.Abs(value)-deadzone)/(1-deadzone), value)
+}
+
+// Returns the raw value of an axis between -1 and 1, without the deadzone.
+func (gamepad *Gamepad) GetRawAxis(axis GamepadAxis) float64 {
+	if axis < 0 || int(axis) >= GAMEPAD_AXIS_COUNT {
+		return 0
+	}
+	return gamepad.axes[axis]
+}
+
+// Returns the position of the left stick.
+func (gamepad *Gamepad) GetLeftStick() Vec2 {
+	return NewVec2(gamepad.GetAxis(GAMEPAD_AXIS_LEFTX), gamepad.GetAxis(GAMEPAD_AXIS_LEFTY))
+}
+
+// Returns the position of the right stick.
+func (gamepad *Gamepad) GetRightStick() Vec2 {
+	return NewVec2(gamepad.GetAxis(GAMEPAD_AXIS_RIGHTX), gamepad.GetAxis(GAMEPAD_AXIS_RIGHTY))
+}
+
+// Sets the deadzone of an axis between 0 and 1.
+func (gamepad *Gamepad) SetDeadzone(axis GamepadAxis, deadzone float64) *Gamepad {
+	if axis >= 0 && int(axis) < GAMEPAD_AXIS_COUNT {
+		gamepad.Deadzones[axis] = deadzone
+	}
+	return gamepad
+}
+
+// Sets the deadzone of all axes between 0 and 1.
+func (gamepad *Gamepad) SetDeadzones(deadzone float64) *Gamepad {
+	for axis := range gamepad.Deadzones {
+		gamepad.Deadzones[axis] = deadzone
+	}
+	return gamepad
+}
+
+// Returns the SDL name of the button, for example "a", "start" or "dpup".
+func (button GamepadButton) String() string {
+	return sdl.GameControllerGetStringForButton(sdl.GameControllerButton(button))
+}
+
+// Returns a gamepad button from its SDL name (see button.String).
+func ParseGamepadButton(name string) (GamepadButton, error) {
+	button := GamepadButton(sdl.GameControllerGetButtonFromString(name))
+	if button == sdl.CONTROLLER_BUTTON_INVALID {
+		return button, fmt.Errorf("unknown gamepad button name %q", name)
+	}
+	return button, nil
+}
+
+// Encodes the gamepad button as its name.
+func (button GamepadButton) MarshalText() ([]byte, error) {
+	return []byte(button.String()), nil
+}
+
+// Decodes a gamepad button from its name.
+func (button *GamepadButton) UnmarshalText(text []byte) error {
+	parsed, err := ParseGamepadButton(string(text))
+	if err != nil {
+		return err
+	}
+	*button = parsed
+	return nil
+}
+
+// Returns the SDL name of the axis, for example "leftx" or "lefttrigger".
+func (axis GamepadAxis) String() string {
+	return sdl.GameControllerGetStringForAxis(sdl.GameControllerAxis(axis))
+}
+
+// Returns a gamepad axis from its SDL name (see axis.String).
+func ParseGamepadAxis(name string) (GamepadAxis, error) {
+	axis := GamepadAxis(sdl.GameControllerGetAxisFromString(name))
+	if axis == sdl.CONTROLLER_AXIS_INVALID {
+		return axis, fmt.Errorf("unknown gamepad axis name %q", name)
+	}
+	return axis, nil
+}
+
+// Encodes the gamepad axis as its name.
+func (axis GamepadAxis) MarshalText() ([]byte, error) {
+	return []byte(axis.String()), nil
+}
+
+// Decodes a gamepad axis from its name.
+func (axis *GamepadAxis) UnmarshalText(text []byte) error {
+	parsed, err := ParseGamepadAxis(string(text))
+	if err != nil {
+		return err
+	}
+	*axis = parsed
+	return nil
+}
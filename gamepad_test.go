@@ -0,0 +1,76 @@
+package fine
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestReadGamepadMappings(t *testing.T) {
+	file := strings.Join([]string{
+		"# Comment",
+		"03000000aaaa,Pad A,a:b0,platform:Linux,",
+		"",
+		"03000000bbbb,Pad B,a:b0,platform:Windows,",
+		"03000000cccc,Pad C,a:b0,",
+		"  03000000dddd,Pad D,platform: Linux ,a:b0  ",
+	}, "\n")
+
+	mappings, err := readGamepadMappings(strings.NewReader(file), "Linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"03000000aaaa,Pad A,a:b0,platform:Linux,", "03000000dddd,Pad D,platform: Linux ,a:b0"}
+	if len(mappings) != len(want) {
+		t.Fatalf("mappings = %q, want %q", mappings, want)
+	}
+	for idx := range want {
+		if mappings[idx] != want[idx] {
+			t.Errorf("mapping %d = %q, want %q", idx, mappings[idx], want[idx])
+		}
+	}
+}
+
+func TestGamepadEvents(t *testing.T) {
+	app := &App{GamepadDeadzone: 0.2}
+	connected := 0
+	app.OnGamepadConnected = func(gamepad *Gamepad, app *App) {
+		connected++
+	}
+
+	// Gamepads without an SDL controller are driven by synthetic events
+	app.handleEvent(&replayedGamepadEvent{sdl.ControllerDeviceEvent{Which: 3}})
+	app.handleEvent(&replayedGamepadEvent{sdl.ControllerDeviceEvent{Which: 3}})
+	gamepad := app.GetGamepad(3)
+	if gamepad == nil || connected != 1 {
+		t.Fatalf("gamepad = %v, connected %d times, want connected once", gamepad, connected)
+	}
+
+	app.handleEvent(&sdl.ControllerButtonEvent{Which: 3, Button: sdl.CONTROLLER_BUTTON_A, State: sdl.PRESSED})
+	app.handleEvent(&sdl.ControllerButtonEvent{Which: 3, Button: sdl.CONTROLLER_BUTTON_A, State: sdl.PRESSED})
+	app.handleEvent(&sdl.ControllerButtonEvent{Which: 7, Button: sdl.CONTROLLER_BUTTON_B, State: sdl.PRESSED})
+	if !gamepad.IsButtonDown(GAMEPAD_BUTTON_A) || !gamepad.IsButtonJustDown(GAMEPAD_BUTTON_A) || len(gamepad.HeldButtons) != 1 {
+		t.Errorf("held buttons = %v, just down = %v, want [A]", gamepad.HeldButtons, gamepad.JustDownButtons)
+	}
+
+	app.clearJustGamepadButtons()
+	app.handleEvent(&sdl.ControllerButtonEvent{Which: 3, Button: sdl.CONTROLLER_BUTTON_A, State: sdl.RELEASED})
+	if gamepad.IsButtonDown(GAMEPAD_BUTTON_A) || gamepad.IsButtonJustDown(GAMEPAD_BUTTON_A) || !gamepad.IsButtonJustUp(GAMEPAD_BUTTON_A) {
+		t.Errorf("held buttons = %v, just up = %v, want A released", gamepad.HeldButtons, gamepad.JustUpButtons)
+	}
+
+	app.handleEvent(&sdl.ControllerAxisEvent{Which: 3, Axis: sdl.CONTROLLER_AXIS_LEFTX, Value: -32768})
+	app.handleEvent(&sdl.ControllerAxisEvent{Which: 3, Axis: sdl.CONTROLLER_AXIS_LEFTY, Value: 3276})
+	if value := gamepad.GetAxis(GAMEPAD_AXIS_LEFTX); value != -1 {
+		t.Errorf("left x = %v, want -1", value)
+	}
+	if value := gamepad.GetRawAxis(GAMEPAD_AXIS_LEFTY); math.Abs(value-0.1) > 1e-4 {
+		t.Errorf("raw left y = %v, want 0.1", value)
+	}
+	// The Y axis is inside the deadzone
+	if stick := gamepad.GetLeftStick(); stick != NewVec2(-1, 0) {
+		t.Errorf("left stick = %v, want {-1 0}", stick)
+	}
+}
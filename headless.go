@@ -0,0 +1,35 @@
+package fine
+
+import "github.com/faiface/beep/speaker"
+
+// Creates a new app that runs without a display. It uses SDL's dummy video
+// driver and a software renderer and doesn't open an audio device, so the
+// app can be driven with RunFrames or RunUntil on machines without a display.
+func NewHeadlessApp(title string, width, height int32) *App {
+	app := newApp(title, width, height)
+	app.Headless = true
+	app.NullAudio = true
+	return app
+}
+
+// Specifies whether the app should run without a display. This must be set
+// before app.Run is called.
+func (app *App) SetHeadless(state bool) *App {
+	app.Headless = state
+	return app
+}
+
+// Specifies whether the app should discard all audio instead of opening an
+// audio device.
+func (app *App) SetNullAudio(state bool) *App {
+	if state == app.NullAudio {
+		return app
+	}
+	app.NullAudio = state
+	if state {
+		speaker.Close()
+	} else {
+		app.initAudio()
+	}
+	return app
+}
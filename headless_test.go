@@ -0,0 +1,58 @@
+package fine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+func TestRunFramesHeadless(t *testing.T) {
+	videoDriver, videoDriverSet := os.LookupEnv("SDL_VIDEODRIVER")
+	app := NewHeadlessApp("headless", 64, 48)
+
+	updates := 0
+	app.SetUpdateFunc(func(dt float64, app *App) {
+		updates++
+	})
+	queuedCalled := false
+	app.After(0, func(app *App) {
+		queuedCalled = true
+	})
+
+	if err := app.RunFrames(3); err != nil {
+		t.Fatalf("RunFrames: %s", err)
+	}
+	if app.Frame != 3 {
+		t.Errorf("drew %d frames, want 3", app.Frame)
+	}
+	if updates != 3 {
+		t.Errorf("update function called %d times, want 3", updates)
+	}
+	if !queuedCalled {
+		t.Error("queued function was not called")
+	}
+	if driver, set := os.LookupEnv("SDL_VIDEODRIVER"); driver != videoDriver || set != videoDriverSet {
+		t.Errorf("SDL_VIDEODRIVER was not restored, it is %q", driver)
+	}
+}
+
+func TestNullAudioPlay(t *testing.T) {
+	app := NewHeadlessApp("audio", 32, 32)
+	buffer := beep.NewBuffer(beep.Format{SampleRate: 100, NumChannels: 2, Precision: 2})
+	buffer.Append(beep.Silence(50))
+	audio := &Audio{Buffer: buffer, Base: 2, app: app}
+
+	app.Time = 1
+	audio.Play().SetVolume(-1)
+	if audio.volumeEffect != nil {
+		t.Error("a stream was created for the speaker with null audio")
+	}
+	if audio.Ended() {
+		t.Error("audio ended right after playing")
+	}
+	app.Time = 1.5
+	if !audio.Ended() {
+		t.Error("audio didn't end after its duration")
+	}
+}
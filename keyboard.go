@@ -1,6 +1,8 @@
 package fine
 
 import (
+	"fmt"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -71,6 +73,36 @@ func (app *App) IsKeyJustUp(key Key) bool {
 	return false
 }
 
+// Returns the name of the key, for example "Space" or "Left Shift". The names
+// are listed next to the key constants.
+func (key Key) String() string {
+	return sdl.GetKeyName(sdl.Keycode(key))
+}
+
+// Returns a key from its name (see key.String). Names are case insensitive.
+func ParseKey(name string) (Key, error) {
+	key := Key(sdl.GetKeyFromName(name))
+	if key == KEY_UNKNOWN {
+		return KEY_UNKNOWN, fmt.Errorf("unknown key name %q", name)
+	}
+	return key, nil
+}
+
+// Encodes the key as its name.
+func (key Key) MarshalText() ([]byte, error) {
+	return []byte(key.String()), nil
+}
+
+// Decodes a key from its name.
+func (key *Key) UnmarshalText(text []byte) error {
+	parsed, err := ParseKey(string(text))
+	if err != nil {
+		return err
+	}
+	*key = parsed
+	return nil
+}
+
 // Keyboard keys.
 type Key int          // Key.
 type KeyDirection int // Key direction (up or down).
@@ -0,0 +1,112 @@
+package fine
+
+import "sort"
+
+// A render layer. Layers are drawn from the lowest to the highest order, and
+// entities in a layer from the lowest to the highest Z index. Entities without
+// a layer are drawn as if they were in a visible world layer with order 0.
+type Layer struct {
+	Name        string // The name of the layer.
+	Order       int    // The draw order of the layer, higher layers are drawn on top.
+	Visible     bool   // Specifies if the entities of the layer are drawn. Hidden entities are still updated.
+	ScreenSpace bool   // Ignore the camera, for HUDs. Positions are relative to the center of the screen.
+}
+
+// The camera used to draw screen space layers.
+var screenSpaceCamera = Camera{Position: NewVec2(0, 0), Zoom: 1}
+
+// Adds a new visible layer to the scene.
+func (scene *Scene) AddLayer(name string, order int) *Layer {
+	layer := &Layer{Name: name, Order: order, Visible: true}
+	scene.Layers = append(scene.Layers, layer)
+	return layer
+}
+
+// Returns a layer of the scene, or nil if it doesn't exist.
+func (scene *Scene) GetLayer(name string) *Layer {
+	for _, layer := range scene.Layers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// Removes a layer from the scene. Entities in the layer are moved to the
+// default layer.
+func (scene *Scene) RemoveLayer(name string) {
+	for idx, layer := range scene.Layers {
+		if layer.Name != name {
+			continue
+		}
+		for _, entity := range scene.Entities {
+			if entity.Layer == layer {
+				entity.Layer = nil
+			}
+		}
+		scene.Layers = append(scene.Layers[:idx], scene.Layers[idx+1:]...)
+		return
+	}
+}
+
+// Sets the draw order of the layer.
+func (layer *Layer) SetOrder(order int) *Layer {
+	layer.Order = order
+	return layer
+}
+
+// Shows or hides the layer.
+func (layer *Layer) SetVisible(visible bool) *Layer {
+	layer.Visible = visible
+	return layer
+}
+
+// Specifies if the layer ignores the camera.
+func (layer *Layer) SetScreenSpace(screenSpace bool) *Layer {
+	layer.ScreenSpace = screenSpace
+	return layer
+}
+
+// Sets the layer of the entity, nil for the default layer.
+func (entity *Entity) SetLayer(layer *Layer) *Entity {
+	entity.Layer = layer
+	return entity
+}
+
+// Sets the Z index of the entity in its layer. Entities with a higher Z index
+// are drawn on top.
+func (entity *Entity) SetZIndex(z int) *Entity {
+	entity.ZIndex = z
+	return entity
+}
+
+func (entity *Entity) layerOrder() int {
+	if entity.Layer == nil {
+		return 0
+	}
+	return entity.Layer.Order
+}
+
+// Entities sorted by layer and Z index. Entities with the same layer order and
+// Z index keep their order in Scene.Entities.
+type drawOrder []*Entity
+
+func (order drawOrder) Len() int      { return len(order) }
+func (order drawOrder) Swap(i, j int) { order[i], order[j] = order[j], order[i] }
+func (order drawOrder) Less(i, j int) bool {
+	layerI, layerJ := order[i].layerOrder(), order[j].layerOrder()
+	if layerI != layerJ {
+		return layerI < layerJ
+	}
+	return order[i].ZIndex < order[j].ZIndex
+}
+
+// Returns the entities of the scene in draw order. The returned slice is
+// reused between frames, Scene.Entities is not modified.
+func (scene *Scene) sortedEntities() []*Entity {
+	scene.drawOrder = append(scene.drawOrder[:0], scene.Entities...)
+	if !sort.IsSorted(scene.drawOrder) {
+		sort.Stable(scene.drawOrder)
+	}
+	return scene.drawOrder
+}
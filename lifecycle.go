@@ -0,0 +1,127 @@
+package fine
+
+import "github.com/faiface/beep"
+
+type OnStartFunc func(app *App) error // Function that is called after the window and renderer are created. Returning an error stops the app.
+type LifecycleFunc func(app *App)     // Function that is called when the app stops, pauses or resumes.
+
+// Sets the start function (called after the window and renderer are created,
+// before the first frame). Sprites, fonts and textures can be loaded here.
+func (app *App) SetStartFunc(newFunc OnStartFunc) *App {
+	app.OnStart = newFunc
+	return app
+}
+
+// Sets the stop function (called after the last frame, before the window and
+// renderer are destroyed).
+func (app *App) SetStopFunc(newFunc LifecycleFunc) *App {
+	app.OnStop = newFunc
+	return app
+}
+
+// Sets the pause function (called when the window is minimized or loses focus).
+func (app *App) SetPauseFunc(newFunc LifecycleFunc) *App {
+	app.OnPause = newFunc
+	return app
+}
+
+// Sets the resume function (called when the window is restored and focused
+// again after pausing).
+func (app *App) SetResumeFunc(newFunc LifecycleFunc) *App {
+	app.OnResume = newFunc
+	return app
+}
+
+// Specifies if updates and audio should be paused automatically while the
+// window is minimized or not focused.
+func (app *App) SetAutoPause(autoPause bool) *App {
+	app.AutoPause = autoPause
+	app.audioPaused.Store(app.IsPaused())
+	return app
+}
+
+// Pauses or resumes app.Update, fixed updates, entity updates, queued
+// functions and audio. Frames are still drawn while paused. The app stays
+// paused when the window is restored or focused again, even with auto pause.
+func (app *App) SetPaused(paused bool) *App {
+	app.Paused = paused
+	app.audioPaused.Store(app.IsPaused())
+	return app
+}
+
+// Checks if the app is paused, either manually or automatically because the
+// window is inactive.
+func (app *App) IsPaused() bool {
+	return app.Paused || (app.AutoPause && app.inactive())
+}
+
+// Checks if the window is minimized or not focused.
+func (app *App) inactive() bool {
+	return app.minimized || app.unfocused
+}
+
+// Updates the minimized and focus state, and pauses or resumes the app if it
+// became inactive or active.
+func (app *App) setInactive(minimized, unfocused bool) {
+	wasInactive := app.inactive()
+	app.minimized, app.unfocused = minimized, unfocused
+	isInactive := app.inactive()
+	if wasInactive == isInactive {
+		return
+	}
+
+	app.audioPaused.Store(app.IsPaused())
+	if isInactive && app.OnPause != nil {
+		app.OnPause(app)
+	} else if !isInactive && app.OnResume != nil {
+		app.OnResume(app)
+	}
+}
+
+// Uploads the textures of sprites that were loaded before the renderer existed.
+func (app *App) loadPendingTextures() error {
+	for _, sprite := range app.LoadedSprites {
+		if sprite.Tex == nil && sprite.Surface != nil {
+			if err := sprite.LoadTexture(app); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Calls the start function after the window and renderer are created.
+func (app *App) start() error {
+	if err := app.loadPendingTextures(); err != nil {
+		return err
+	}
+	if app.OnStart != nil {
+		return app.OnStart(app)
+	}
+	return nil
+}
+
+// Calls the stop function before the window and renderer are destroyed.
+func (app *App) stop() {
+	if app.OnStop != nil {
+		app.OnStop(app)
+	}
+	app.minimized, app.unfocused = false, false
+	app.audioPaused.Store(app.IsPaused())
+}
+
+// Plays silence without advancing the stream while the app audio is paused.
+type pausableStreamer struct {
+	beep.Streamer
+	app *App
+}
+
+func (streamer pausableStreamer) Stream(samples [][2]float64) (int, bool) {
+	if streamer.app.audioPaused.Load() {
+		for idx := range samples {
+			samples[idx] = [2]float64{}
+		}
+		return len(samples), true
+	}
+	return streamer.Streamer.Stream(samples)
+}
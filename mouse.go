@@ -1,9 +1,15 @@
 package fine
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+type OnMouseMoveFunc func(x, y, deltaX, deltaY int32, app *App) // Function that is called when the mouse moves. Receives the mouse position, the movement and the app.
+
 type ButtonInfo struct {
 	MouseID uint32      // The ID of the mouse.
 	Button  MouseButton // The mouse button that is pressed.
@@ -18,6 +24,7 @@ func (app *App) handleMouseButtonEvent(event *sdl.MouseButtonEvent) {
 		Button:  button,
 		Clicks:  int(event.Clicks),
 	}
+	app.setMousePos(event.X, event.Y)
 
 	if event.State == sdl.RELEASED {
 		app.JustUpMouseButtons = append(app.JustUpMouseButtons, buttonInfo)
@@ -37,10 +44,65 @@ func (app *App) handleMouseButtonEvent(event *sdl.MouseButtonEvent) {
 	}
 }
 
-// Get the mouse X and Y coordinates on screen.
+func (app *App) handleMouseMotionEvent(event *sdl.MouseMotionEvent) {
+	// Scaled movements can be smaller than a pixel, keep the fractions so slow
+	// movements aren't lost
+	deltaX, deltaY := app.windowToLogicalDelta(event.XRel, event.YRel)
+	app.mouseDeltaX += deltaX
+	app.mouseDeltaY += deltaY
+	previousX, previousY := app.MouseDeltaX, app.MouseDeltaY
+	app.MouseDeltaX, app.MouseDeltaY = int32(math.Trunc(app.mouseDeltaX)), int32(math.Trunc(app.mouseDeltaY))
+
+	app.setMousePos(event.X, event.Y)
+
+	if app.OnMouseMove != nil {
+		app.OnMouseMove(app.MouseX, app.MouseY, app.MouseDeltaX-previousX, app.MouseDeltaY-previousY, app)
+	}
+}
+
+// Stores the mouse position of an event, converted to render coordinates.
+func (app *App) setMousePos(x, y int32) {
+	logicalX, logicalY := app.WindowToLogical(int(x), int(y))
+	app.MouseX, app.MouseY = int32(logicalX), int32(logicalY)
+}
+
+// Sets the mouse move function (called when the mouse moves).
+func (app *App) SetMouseMoveFunc(newFunc OnMouseMoveFunc) *App {
+	app.OnMouseMove = newFunc
+	return app
+}
+
+// Enables or disables relative mouse mode. In relative mode the cursor is
+// hidden and captured by the window, and only app.MouseDeltaX and
+// app.MouseDeltaY change. Useful for aiming.
+func (app *App) SetRelativeMouseMode(state bool) error {
+	if sdl.SetRelativeMouseMode(state) < 0 {
+		return sdl.GetError()
+	}
+	return nil
+}
+
+// Checks if relative mouse mode is enabled.
+func (app *App) IsRelativeMouseMode() bool {
+	return sdl.GetRelativeMouseMode()
+}
+
+// Returns the mouse movement on this frame.
+func (app *App) GetMouseDelta() (int, int) {
+	return int(app.MouseDeltaX), int(app.MouseDeltaY)
+}
+
+// Get the world position under the mouse, using the main camera.
+func (app *App) GetMouseWorldPos() Vec2 {
+	x, y := app.GetMousePos()
+	return app.ScreenToWorld(x, y)
+}
+
+// Get the mouse X and Y coordinates on screen, in render coordinates. The
+// position is updated by the mouse events, so it is also correct while
+// replaying input.
 func (app *App) GetMousePos() (int, int) {
-	x, y, _ := sdl.GetMouseState()
-	return int(x), int(y)
+	return int(app.MouseX), int(app.MouseY)
 }
 
 // Checks if a mouse button is currently pressed.
@@ -83,3 +145,44 @@ const (
 	MBUTTON_X1     MouseButton = sdl.BUTTON_X1     // X1 mouse button.
 	MBUTTON_X2     MouseButton = sdl.BUTTON_X2     // X2 mouse button.
 )
+
+var mouseButtonNames = map[MouseButton]string{
+	MBUTTON_LEFT:   "left",
+	MBUTTON_MIDDLE: "middle",
+	MBUTTON_RIGHT:  "right",
+	MBUTTON_X1:     "x1",
+	MBUTTON_X2:     "x2",
+}
+
+// Returns the name of the mouse button ("left", "middle", "right", "x1" or "x2").
+func (button MouseButton) String() string {
+	if name, ok := mouseButtonNames[button]; ok {
+		return name
+	}
+	return fmt.Sprintf("button%d", int(button))
+}
+
+// Returns a mouse button from its name (see button.String).
+func ParseMouseButton(name string) (MouseButton, error) {
+	for button, buttonName := range mouseButtonNames {
+		if strings.EqualFold(name, buttonName) {
+			return button, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown mouse button name %q", name)
+}
+
+// Encodes the mouse button as its name.
+func (button MouseButton) MarshalText() ([]byte, error) {
+	return []byte(button.String()), nil
+}
+
+// Decodes a mouse button from its name.
+func (button *MouseButton) UnmarshalText(text []byte) error {
+	parsed, err := ParseMouseButton(string(text))
+	if err != nil {
+		return err
+	}
+	*button = parsed
+	return nil
+}
@@ -0,0 +1,190 @@
+package fine
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// The default amount of frames kept by FrameStats.
+const DefaultFrameStatsSize = 300
+
+// Rolling frame time statistics over the last frames. All times are in seconds.
+type FrameStats struct {
+	times []float64
+	next  int
+	count int
+}
+
+// Creates new frame statistics that keep the last size frames.
+func NewFrameStats(size int) *FrameStats {
+	if size < 1 {
+		size = 1
+	}
+	return &FrameStats{times: make([]float64, size)}
+}
+
+// Adds the duration of a frame.
+func (stats *FrameStats) Add(frameTime float64) {
+	stats.times[stats.next] = frameTime
+	stats.next = (stats.next + 1) % len(stats.times)
+	if stats.count < len(stats.times) {
+		stats.count++
+	}
+}
+
+// Removes all frames.
+func (stats *FrameStats) Reset() {
+	stats.next, stats.count = 0, 0
+}
+
+// Returns the amount of frames in the statistics.
+func (stats *FrameStats) Count() int {
+	return stats.count
+}
+
+// Returns the frame times from the oldest to the newest frame.
+func (stats *FrameStats) Times() []float64 {
+	times := make([]float64, 0, stats.count)
+	start := (stats.next - stats.count + len(stats.times)) % len(stats.times)
+	for i := 0; i < stats.count; i++ {
+		times = append(times, stats.times[(start+i)%len(stats.times)])
+	}
+	return times
+}
+
+// Returns the average frame time.
+func (stats *FrameStats) Average() float64 {
+	if stats.count == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, frameTime := range stats.times[:stats.count] {
+		sum += frameTime
+	}
+	return sum / float64(stats.count)
+}
+
+// Returns the shortest frame time.
+func (stats *FrameStats) Min() float64 {
+	if stats.count == 0 {
+		return 0
+	}
+	min := math.Inf(1)
+	for _, frameTime := range stats.times[:stats.count] {
+		min = math.Min(min, frameTime)
+	}
+	return min
+}
+
+// Returns the longest frame time.
+func (stats *FrameStats) Max() float64 {
+	if stats.count == 0 {
+		return 0
+	}
+	max := math.Inf(-1)
+	for _, frameTime := range stats.times[:stats.count] {
+		max = math.Max(max, frameTime)
+	}
+	return max
+}
+
+// Returns the average frame time of the slowest percent of frames (for
+// example, 1 for the 1% lows).
+func (stats *FrameStats) SlowestAverage(percent float64) float64 {
+	if stats.count == 0 {
+		return 0
+	}
+	times := append([]float64(nil), stats.times[:stats.count]...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(times)))
+
+	amount := int(math.Ceil(float64(len(times)) * percent / 100))
+	if amount < 1 {
+		amount = 1
+	} else if amount > len(times) {
+		amount = len(times)
+	}
+
+	sum := 0.0
+	for _, frameTime := range times[:amount] {
+		sum += frameTime
+	}
+	return sum / float64(amount)
+}
+
+// Returns the average frames per second.
+func (stats *FrameStats) AverageFPS() float64 {
+	return fpsFromFrameTime(stats.Average())
+}
+
+// Returns the 1% low frames per second: the average FPS of the slowest 1% of frames.
+func (stats *FrameStats) OnePercentLowFPS() float64 {
+	return fpsFromFrameTime(stats.SlowestAverage(1))
+}
+
+// Counts the frames in buckets of bucketSize seconds, starting at 0. Frames
+// longer than the last bucket are counted in the last bucket.
+func (stats *FrameStats) Histogram(buckets int, bucketSize float64) []int {
+	if buckets < 1 || bucketSize <= 0 {
+		return nil
+	}
+	histogram := make([]int, buckets)
+	for _, frameTime := range stats.times[:stats.count] {
+		bucket := int(frameTime / bucketSize)
+		if bucket >= buckets {
+			bucket = buckets - 1
+		} else if bucket < 0 {
+			bucket = 0
+		}
+		histogram[bucket]++
+	}
+	return histogram
+}
+
+func fpsFromFrameTime(frameTime float64) float64 {
+	if frameTime <= 0 {
+		return 0
+	}
+	return 1 / frameTime
+}
+
+// Sets the maximum amount of frames per second, 0 means unlimited. This works
+// without vsync, set the swap interval to 0 to disable vsync.
+func (app *App) SetTargetFPS(fps float64) *App {
+	app.TargetFPS = fps
+	return app
+}
+
+// Returns the average frames per second over the last frames.
+func (app *App) GetAverageFPS() float64 {
+	return app.FrameStats.AverageFPS()
+}
+
+// Waits until it's time to start the next frame, according to app.TargetFPS.
+func (app *App) limitFrameRate() {
+	now := time.Now()
+	if app.TargetFPS <= 0 || app.frameStart.IsZero() {
+		app.frameStart = now
+		return
+	}
+
+	frameDuration := time.Duration(float64(time.Second) / app.TargetFPS)
+	target := app.frameStart.Add(frameDuration)
+
+	// Sleeping isn't precise, so sleep for most of the remaining time and
+	// wait for the rest
+	if remaining := target.Sub(now); remaining > 2*time.Millisecond {
+		time.Sleep(remaining - 2*time.Millisecond)
+	}
+	for time.Now().Before(target) {
+		runtime.Gosched()
+	}
+
+	// Don't try to catch up if we're more than a frame behind
+	if time.Since(target) > frameDuration {
+		app.frameStart = time.Now()
+	} else {
+		app.frameStart = target
+	}
+}
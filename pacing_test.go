@@ -0,0 +1,82 @@
+package fine
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameStats(t *testing.T) {
+	stats := NewFrameStats(4)
+	if stats.Average() != 0 || stats.AverageFPS() != 0 || stats.Min() != 0 || stats.Max() != 0 {
+		t.Error("empty statistics should be 0")
+	}
+
+	for _, frameTime := range []float64{0.02, 0.01, 0.04, 0.01} {
+		stats.Add(frameTime)
+	}
+	if math.Abs(stats.Average()-0.02) > 1e-9 || math.Abs(stats.AverageFPS()-50) > 1e-6 {
+		t.Errorf("average = %v (%v FPS), want 0.02 (50 FPS)", stats.Average(), stats.AverageFPS())
+	}
+	if stats.Min() != 0.01 || stats.Max() != 0.04 {
+		t.Errorf("min, max = %v, %v, want 0.01, 0.04", stats.Min(), stats.Max())
+	}
+	// The 1% low is the slowest frame, there are less than 100 frames
+	if low := stats.OnePercentLowFPS(); math.Abs(low-25) > 1e-6 {
+		t.Errorf("1%% low = %v FPS, want 25", low)
+	}
+	if slowest := stats.SlowestAverage(50); math.Abs(slowest-0.03) > 1e-9 {
+		t.Errorf("slowest 50%% = %v, want 0.03", slowest)
+	}
+}
+
+func TestFrameStatsWrapAround(t *testing.T) {
+	stats := NewFrameStats(3)
+	for _, frameTime := range []float64{0.5, 0.01, 0.02, 0.03, 0.04} {
+		stats.Add(frameTime)
+	}
+
+	// Only the last 3 frames are kept
+	if got := stats.Times(); !reflect.DeepEqual(got, []float64{0.02, 0.03, 0.04}) || stats.Count() != 3 {
+		t.Errorf("times = %v, want [0.02 0.03 0.04]", got)
+	}
+	if stats.Max() != 0.04 || math.Abs(stats.Average()-0.03) > 1e-9 {
+		t.Errorf("max, average = %v, %v, want 0.04, 0.03", stats.Max(), stats.Average())
+	}
+
+	stats.Reset()
+	stats.Add(0.1)
+	if got := stats.Times(); !reflect.DeepEqual(got, []float64{0.1}) || stats.Average() != 0.1 {
+		t.Errorf("times after Reset = %v, want [0.1]", got)
+	}
+}
+
+func TestFrameStatsHistogram(t *testing.T) {
+	stats := NewFrameStats(10)
+	for _, frameTime := range []float64{0.005, 0.012, 0.016, 0.018, 0.5} {
+		stats.Add(frameTime)
+	}
+
+	// The last bucket also counts the longer frames
+	if got := stats.Histogram(3, 0.01); !reflect.DeepEqual(got, []int{1, 3, 1}) {
+		t.Errorf("histogram = %v, want [1 3 1]", got)
+	}
+	if got := stats.Histogram(0, 0.01); got != nil {
+		t.Errorf("histogram without buckets = %v, want nil", got)
+	}
+}
+
+func TestLimitFrameRate(t *testing.T) {
+	app := &App{}
+	app.SetTargetFPS(50)
+
+	app.limitFrameRate()
+	start := time.Now()
+	app.limitFrameRate()
+	app.limitFrameRate()
+	// Two frames at 50 FPS take at least 40ms
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("two frames took %v, want about 40ms", elapsed)
+	}
+}
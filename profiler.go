@@ -0,0 +1,281 @@
+package fine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/veandco/go-sdl2/gfx"
+)
+
+// The default amount of frames kept in the profiler history.
+const DefaultProfilerHistorySize = 600
+
+type ProfilePhase int
+
+const (
+	PROFILE_EVENTS        ProfilePhase = iota // Polling and handling events.
+	PROFILE_UPDATE                            // Actions, fixed updates, app.Update and queued functions.
+	PROFILE_ENTITY_UPDATE                     // Entity update functions.
+	PROFILE_DRAW                              // Clearing the screen, drawing entities and app.PostRender.
+	PROFILE_PRESENT                           // Presenting the frame.
+	PROFILE_PHASE_COUNT                       // The amount of profile phases.
+)
+
+var profilePhaseNames = [PROFILE_PHASE_COUNT]string{
+	PROFILE_EVENTS:        "events",
+	PROFILE_UPDATE:        "update",
+	PROFILE_ENTITY_UPDATE: "entity_update",
+	PROFILE_DRAW:          "draw",
+	PROFILE_PRESENT:       "present",
+}
+
+// Returns the name of the profile phase.
+func (phase ProfilePhase) String() string {
+	if phase < 0 || phase >= PROFILE_PHASE_COUNT {
+		return fmt.Sprintf("ProfilePhase(%d)", int(phase))
+	}
+	return profilePhaseNames[phase]
+}
+
+// Profiling information of a single frame.
+type ProfileSample struct {
+	Frame          int                                // The frame number.
+	Total          time.Duration                      // The duration of the whole frame, without waiting for app.TargetFPS.
+	Phases         [PROFILE_PHASE_COUNT]time.Duration // The duration of each phase.
+	EntitiesDrawn  int                                // Entities that were drawn.
+	EntitiesCulled int                                // Entities that were skipped because they were off the screen.
+	TextureCopies  int                                // Textures copied to the renderer.
+}
+
+// Measures how long each part of a frame takes. Set app.Profiler (or call
+// app.EnableProfiler) to start profiling.
+type Profiler struct {
+	ShowOverlay bool            // Draw the profiling information of the last frame on the screen.
+	HistorySize int             // The maximum amount of samples kept in History.
+	History     []ProfileSample // Samples of the last frames, from the oldest to the newest.
+	current     ProfileSample
+	frameStart  time.Time
+}
+
+// Creates a new profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{HistorySize: DefaultProfilerHistorySize}
+}
+
+// Starts profiling every frame and returns the profiler.
+func (app *App) EnableProfiler() *Profiler {
+	if app.Profiler == nil {
+		app.Profiler = NewProfiler()
+	}
+	return app.Profiler
+}
+
+// Stops profiling.
+func (app *App) DisableProfiler() {
+	app.Profiler = nil
+}
+
+// Returns the sample of the last finished frame.
+func (profiler *Profiler) Last() (ProfileSample, bool) {
+	if len(profiler.History) == 0 {
+		return ProfileSample{}, false
+	}
+	return profiler.History[len(profiler.History)-1], true
+}
+
+// Returns the average of all samples in the history.
+func (profiler *Profiler) Average() ProfileSample {
+	var average ProfileSample
+	if len(profiler.History) == 0 {
+		return average
+	}
+
+	count := len(profiler.History)
+	for _, sample := range profiler.History {
+		average.Total += sample.Total
+		for phase := range sample.Phases {
+			average.Phases[phase] += sample.Phases[phase]
+		}
+		average.EntitiesDrawn += sample.EntitiesDrawn
+		average.EntitiesCulled += sample.EntitiesCulled
+		average.TextureCopies += sample.TextureCopies
+	}
+
+	average.Frame = profiler.History[count-1].Frame
+	average.Total /= time.Duration(count)
+	for phase := range average.Phases {
+		average.Phases[phase] /= time.Duration(count)
+	}
+	average.EntitiesDrawn /= count
+	average.EntitiesCulled /= count
+	average.TextureCopies /= count
+	return average
+}
+
+// Removes all samples from the history.
+func (profiler *Profiler) Reset() {
+	profiler.History = nil
+}
+
+// Writes all samples in the history as CSV, with a header row. Durations are
+// in milliseconds.
+func (profiler *Profiler) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+
+	header := []string{"frame", "total_ms"}
+	for phase := ProfilePhase(0); phase < PROFILE_PHASE_COUNT; phase++ {
+		header = append(header, phase.String()+"_ms")
+	}
+	header = append(header, "entities_drawn", "entities_culled", "texture_copies")
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	for _, sample := range profiler.History {
+		record := []string{strconv.Itoa(sample.Frame), formatMilliseconds(sample.Total)}
+		for _, duration := range sample.Phases {
+			record = append(record, formatMilliseconds(duration))
+		}
+		record = append(
+			record,
+			strconv.Itoa(sample.EntitiesDrawn),
+			strconv.Itoa(sample.EntitiesCulled),
+			strconv.Itoa(sample.TextureCopies),
+		)
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Writes all samples in the history to a CSV file.
+func (profiler *Profiler) SaveCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := profiler.WriteCSV(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func formatMilliseconds(duration time.Duration) string {
+	return strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// The profiler methods below are called by the draw loop and do nothing when
+// profiling is disabled (the profiler is nil).
+
+func (profiler *Profiler) beginFrame(frame int) {
+	if profiler == nil {
+		return
+	}
+	profiler.current = ProfileSample{Frame: frame}
+	profiler.frameStart = time.Now()
+}
+
+func (profiler *Profiler) endFrame() {
+	if profiler == nil {
+		return
+	}
+	profiler.current.Total = time.Since(profiler.frameStart)
+	profiler.History = append(profiler.History, profiler.current)
+	if profiler.HistorySize > 0 && len(profiler.History) > profiler.HistorySize {
+		profiler.History = profiler.History[len(profiler.History)-profiler.HistorySize:]
+	}
+}
+
+func (profiler *Profiler) begin() time.Time {
+	if profiler == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (profiler *Profiler) end(phase ProfilePhase, start time.Time) {
+	if profiler == nil {
+		return
+	}
+	profiler.current.Phases[phase] += time.Since(start)
+}
+
+func (profiler *Profiler) culledCount() int {
+	if profiler == nil {
+		return 0
+	}
+	return profiler.current.EntitiesCulled
+}
+
+// Counts an entity as drawn if no entity was culled since culledBefore.
+func (profiler *Profiler) countDrawn(culledBefore int) {
+	if profiler == nil || profiler.current.EntitiesCulled != culledBefore {
+		return
+	}
+	profiler.current.EntitiesDrawn++
+}
+
+func (profiler *Profiler) countCulled() {
+	if profiler == nil {
+		return
+	}
+	profiler.current.EntitiesCulled++
+}
+
+func (profiler *Profiler) countTextureCopy() {
+	if profiler == nil {
+		return
+	}
+	profiler.current.TextureCopies++
+}
+
+// Draws the profiling information of the last frame in the top left corner of
+// the screen.
+func (app *App) drawProfilerOverlay() {
+	if app.Profiler == nil || !app.Profiler.ShowOverlay {
+		return
+	}
+	sample, ok := app.Profiler.Last()
+	if !ok {
+		return
+	}
+
+	lines := []string{
+		fmt.Sprintf("fps %.1f (avg %.1f, 1%% low %.1f)", app.GetFPS(), app.GetAverageFPS(), app.FrameStats.OnePercentLowFPS()),
+		fmt.Sprintf("frame %s", formatMilliseconds(sample.Total)+"ms"),
+	}
+	for phase, duration := range sample.Phases {
+		lines = append(lines, fmt.Sprintf("%s %sms", ProfilePhase(phase), formatMilliseconds(duration)))
+	}
+	lines = append(
+		lines,
+		fmt.Sprintf("entities %d drawn, %d culled", sample.EntitiesDrawn, sample.EntitiesCulled),
+		fmt.Sprintf("texture copies %d", sample.TextureCopies),
+	)
+
+	// The built-in SDL_gfx font is 8x8 pixels
+	const lineHeight, padding = 10, 4
+	width := 0
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	gfx.BoxRGBA(
+		app.Renderer,
+		0, 0,
+		int32(width*8+padding*2), int32(len(lines)*lineHeight+padding*2),
+		0, 0, 0, 180,
+	)
+	for idx, line := range lines {
+		gfx.StringRGBA(app.Renderer, padding, int32(padding+idx*lineHeight), line, 255, 255, 255, 255)
+	}
+}
@@ -0,0 +1,224 @@
+package fine
+
+import (
+	"math"
+	"sort"
+)
+
+// Iterates over the entities matching a query. The entities are collected when
+// the query is made and each one is checked again when it is reached, so
+// entities can be created, changed and destroyed while iterating. Destroyed
+// entities are skipped.
+//
+//	for it := app.Scene.FindAllByTag("enemy"); it.Next(); {
+//		it.Entity().Destroy()
+//	}
+type EntityIterator struct {
+	entities []*Entity
+	filter   func(entity *Entity) bool
+	index    int
+	current  *Entity
+}
+
+func newEntityIterator(entities []*Entity, filter func(entity *Entity) bool) *EntityIterator {
+	return &EntityIterator{
+		entities: append([]*Entity(nil), entities...),
+		filter:   filter,
+	}
+}
+
+// Advances to the next matching entity. Returns false when there are no more entities.
+func (it *EntityIterator) Next() bool {
+	for it.index < len(it.entities) {
+		entity := it.entities[it.index]
+		it.index++
+		if !entity.destroyed && (it.filter == nil || it.filter(entity)) {
+			it.current = entity
+			return true
+		}
+	}
+	it.current = nil
+	return false
+}
+
+// Returns the current entity.
+func (it *EntityIterator) Entity() *Entity {
+	return it.current
+}
+
+// Returns all remaining matching entities.
+func (it *EntityIterator) Collect() []*Entity {
+	var entities []*Entity
+	for it.Next() {
+		entities = append(entities, it.current)
+	}
+	return entities
+}
+
+// Returns the next matching entity, or nil if there are no more entities.
+func (it *EntityIterator) First() *Entity {
+	if it.Next() {
+		return it.current
+	}
+	return nil
+}
+
+// Sets the name of the entity.
+func (entity *Entity) SetName(name string) *Entity {
+	entity.Name = name
+	return entity
+}
+
+// Adds tags to the entity. Tags that the entity already has are ignored.
+func (entity *Entity) AddTag(tags ...string) *Entity {
+	for _, tag := range tags {
+		if !entity.HasTag(tag) {
+			entity.Tags = append(entity.Tags, tag)
+		}
+	}
+	return entity
+}
+
+// Removes a tag from the entity.
+func (entity *Entity) RemoveTag(tag string) *Entity {
+	for idx, entityTag := range entity.Tags {
+		if entityTag == tag {
+			entity.Tags = append(entity.Tags[:idx], entity.Tags[idx+1:]...)
+			break
+		}
+	}
+	return entity
+}
+
+// Checks if the entity has a tag.
+func (entity *Entity) HasTag(tag string) bool {
+	for _, entityTag := range entity.Tags {
+		if entityTag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the world position of the top left corner and the size of the
+// entity, ignoring rotation. Circles are centered on their position and
+// polygons are bounded by their points.
+func (entity *Entity) GetBounds() (Vec2, Vec2) {
+	position := entity.WorldPosition()
+	if entity.Texture != nil {
+		// entity.Width and entity.Height are only set when the texture is
+		// drawn, so use the size of the texture
+		scale := entity.WorldTransform().Scale
+		return position, NewVec2(
+			float64(entity.Texture.Width)*math.Abs(scale.X),
+			float64(entity.Texture.Height)*math.Abs(scale.Y),
+		)
+	}
+
+	switch shape := entity.Shape.(type) {
+	case *CircleShape:
+		return NewVec2(position.X-shape.Radius, position.Y-shape.Radius), NewVec2(shape.Radius*2, shape.Radius*2)
+	case *Polygon:
+		// The points of polygons are world positions
+		minX := math.Min(shape.Point1.X, math.Min(shape.Point2.X, shape.Point3.X))
+		minY := math.Min(shape.Point1.Y, math.Min(shape.Point2.Y, shape.Point3.Y))
+		maxX := math.Max(shape.Point1.X, math.Max(shape.Point2.X, shape.Point3.X))
+		maxY := math.Max(shape.Point1.Y, math.Max(shape.Point2.Y, shape.Point3.Y))
+		return NewVec2(minX, minY), NewVec2(maxX-minX, maxY-minY)
+	}
+	return position, NewVec2(entity.Width, entity.Height)
+}
+
+// Checks if a world position is inside the bounds of the entity.
+func (entity *Entity) ContainsPoint(point Vec2) bool {
+	position, size := entity.GetBounds()
+	return point.X >= position.X && point.X <= position.X+size.X &&
+		point.Y >= position.Y && point.Y <= position.Y+size.Y
+}
+
+// Returns the entities matching a filter, in the order of Scene.Entities.
+func (scene *Scene) Query(filter func(entity *Entity) bool) *EntityIterator {
+	return newEntityIterator(scene.Entities, filter)
+}
+
+// Returns the first entity with a name, or nil if there is none.
+func (scene *Scene) FindByName(name string) *Entity {
+	return scene.FindAllByName(name).First()
+}
+
+// Returns all entities with a name.
+func (scene *Scene) FindAllByName(name string) *EntityIterator {
+	return scene.Query(func(entity *Entity) bool {
+		return entity.Name == name
+	})
+}
+
+// Returns the first entity with a tag, or nil if there is none.
+func (scene *Scene) FindByTag(tag string) *Entity {
+	return scene.FindAllByTag(tag).First()
+}
+
+// Returns all entities with a tag.
+func (scene *Scene) FindAllByTag(tag string) *EntityIterator {
+	return scene.Query(func(entity *Entity) bool {
+		return entity.HasTag(tag)
+	})
+}
+
+// Returns all entities whose bounds overlap a rectangle in the world.
+func (scene *Scene) FindInRect(position, size Vec2) *EntityIterator {
+	return scene.Query(func(entity *Entity) bool {
+		entityPosition, entitySize := entity.GetBounds()
+		return !(entityPosition.X+entitySize.X < position.X ||
+			entityPosition.Y+entitySize.Y < position.Y ||
+			entityPosition.X > position.X+size.X ||
+			entityPosition.Y > position.Y+size.Y)
+	})
+}
+
+// Returns all entities whose bounds overlap a circle in the world.
+func (scene *Scene) FindInRadius(center Vec2, radius float64) *EntityIterator {
+	return scene.Query(func(entity *Entity) bool {
+		position, size := entity.GetBounds()
+		// The closest point of the bounds to the center
+		closestX := math.Max(position.X, math.Min(center.X, position.X+size.X))
+		closestY := math.Max(position.Y, math.Min(center.Y, position.Y+size.Y))
+		return math.Hypot(center.X-closestX, center.Y-closestY) <= radius
+	})
+}
+
+// Returns all entities under a world position, from the topmost (last drawn)
+// to the bottom one. Entities that are not drawn (hidden or in a hidden layer)
+// are skipped. Use app.GetMouseWorldPos to find the entities under the mouse.
+func (scene *Scene) FindAtPoint(point Vec2) *EntityIterator {
+	// Don't use the draw order buffer of the scene, it may be in use
+	sorted := append(drawOrder(nil), scene.Entities...)
+	sort.Stable(sorted)
+	entities := make([]*Entity, len(sorted))
+	for idx, entity := range sorted {
+		entities[len(sorted)-1-idx] = entity
+	}
+	return &EntityIterator{
+		entities: entities,
+		filter: func(entity *Entity) bool {
+			// Only find entities that are drawn
+			if layer := entity.Layer; layer != nil && !layer.Visible {
+				return false
+			}
+			if !entity.WorldTransform().Visible {
+				return false
+			}
+			return entity.ContainsPoint(entity.layerPoint(point))
+		},
+	}
+}
+
+// Converts a world position to the space the entity is drawn in. Screen space
+// layers ignore the camera, so the position on the screen is used for them.
+func (entity *Entity) layerPoint(point Vec2) Vec2 {
+	if entity.Layer == nil || !entity.Layer.ScreenSpace || entity.app == nil {
+		return point
+	}
+	camera := entity.app.Camera
+	return NewVec2(point.X*camera.Zoom-camera.Position.X, point.Y*camera.Zoom-camera.Position.Y)
+}
@@ -0,0 +1,122 @@
+package fine
+
+import (
+	"testing"
+)
+
+func names(entities []*Entity) []string {
+	var result []string
+	for _, entity := range entities {
+		result = append(result, entity.Name)
+	}
+	return result
+}
+
+func equalNames(got []*Entity, want ...string) bool {
+	gotNames := names(got)
+	if len(gotNames) != len(want) {
+		return false
+	}
+	for idx := range want {
+		if gotNames[idx] != want[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGetBoundsOfUndrawnTexture(t *testing.T) {
+	entity := newTestEntity(NewVec2(10, 20), 8, 4)
+	entity.Scale = NewVec2(2, 3)
+
+	position, size := entity.GetBounds()
+	if position != NewVec2(10, 20) || size != NewVec2(16, 12) {
+		t.Errorf("GetBounds = %v, %v, want {10 20}, {16 12}", position, size)
+	}
+}
+
+func TestGetBoundsOfCircle(t *testing.T) {
+	entity := &Entity{Position: NewVec2(50, 50), Scale: NewVec2(1, 1), Shape: &CircleShape{Radius: 5}}
+
+	position, size := entity.GetBounds()
+	if position != NewVec2(45, 45) || size != NewVec2(10, 10) {
+		t.Errorf("GetBounds = %v, %v, want {45 45}, {10 10}", position, size)
+	}
+	if !entity.ContainsPoint(NewVec2(47, 53)) || entity.ContainsPoint(NewVec2(56, 50)) {
+		t.Error("ContainsPoint doesn't use the circle bounds")
+	}
+}
+
+func TestFindByNameAndTag(t *testing.T) {
+	scene := NewScene()
+	scene.Add(newTestEntity(NewVec2(0, 0), 1, 1).SetName("player").AddTag("alive"))
+	scene.Add(newTestEntity(NewVec2(0, 0), 1, 1).SetName("enemy").AddTag("alive", "hostile"))
+	scene.Add(newTestEntity(NewVec2(0, 0), 1, 1).SetName("enemy").AddTag("hostile"))
+
+	if entity := scene.FindByName("player"); entity == nil || entity.Name != "player" {
+		t.Errorf("FindByName(player) = %v", entity)
+	}
+	if count := len(scene.FindAllByName("enemy").Collect()); count != 2 {
+		t.Errorf("found %d enemies, want 2", count)
+	}
+	if entity := scene.FindByName("missing"); entity != nil {
+		t.Errorf("FindByName(missing) = %v, want nil", entity)
+	}
+
+	// Destroying entities while iterating doesn't skip any
+	for it := scene.FindAllByTag("hostile"); it.Next(); {
+		it.Entity().Destroy()
+	}
+	if !equalNames(scene.Entities, "player") {
+		t.Errorf("entities after destroying the hostile ones: %v", names(scene.Entities))
+	}
+}
+
+func TestFindInRectAndRadius(t *testing.T) {
+	scene := NewScene()
+	scene.Add(newTestEntity(NewVec2(0, 0), 10, 10).SetName("a"))
+	scene.Add(newTestEntity(NewVec2(100, 100), 10, 10).SetName("b"))
+	scene.Add(&Entity{Name: "circle", Position: NewVec2(50, 0), Scale: NewVec2(1, 1), Visible: true, Shape: &CircleShape{Radius: 5}})
+
+	if got := scene.FindInRect(NewVec2(5, -20), NewVec2(42, 40)).Collect(); !equalNames(got, "a", "circle") {
+		t.Errorf("FindInRect = %v, want [a circle]", names(got))
+	}
+	if got := scene.FindInRadius(NewVec2(115, 105), 5).Collect(); !equalNames(got, "b") {
+		t.Errorf("FindInRadius = %v, want [b]", names(got))
+	}
+}
+
+func TestFindAtPoint(t *testing.T) {
+	scene := NewScene()
+	hidden := scene.AddLayer("hidden", 5).SetVisible(false)
+	top := scene.AddLayer("top", 1)
+
+	scene.Add(newTestEntity(NewVec2(0, 0), 10, 10).SetName("bottom"))
+	scene.Add(newTestEntity(NewVec2(0, 0), 10, 10).SetName("top").SetLayer(top))
+	scene.Add(newTestEntity(NewVec2(0, 0), 10, 10).SetName("middle").SetZIndex(1))
+	scene.Add(newTestEntity(NewVec2(0, 0), 10, 10).SetName("hidden layer").SetLayer(hidden))
+	invisible := newTestEntity(NewVec2(0, 0), 10, 10).SetName("invisible")
+	invisible.Visible = false
+	scene.Add(invisible)
+
+	if got := scene.FindAtPoint(NewVec2(5, 5)).Collect(); !equalNames(got, "top", "middle", "bottom") {
+		t.Errorf("FindAtPoint = %v, want [top middle bottom]", names(got))
+	}
+}
+
+func TestFindAtPointScreenSpace(t *testing.T) {
+	app := &App{Camera: &Camera{Position: NewVec2(100, 0), Zoom: 1}}
+	scene := NewScene()
+	hud := scene.AddLayer("hud", 1).SetScreenSpace(true)
+	button := newTestEntity(NewVec2(0, 0), 10, 10).SetName("button").SetLayer(hud)
+	button.app = app
+	scene.Add(button)
+
+	// The camera is moved, but the button stays at the same place on the screen
+	if got := scene.FindAtPoint(NewVec2(105, 5)).Collect(); !equalNames(got, "button") {
+		t.Errorf("FindAtPoint = %v, want [button]", names(got))
+	}
+	if got := scene.FindAtPoint(NewVec2(5, 5)).Collect(); len(got) != 0 {
+		t.Errorf("FindAtPoint = %v, want no entities", names(got))
+	}
+}
@@ -0,0 +1,620 @@
+package fine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Function that is called when a replay has finished playing.
+type ReplayEndFunc func(app *App)
+
+// Recorded event kinds.
+const (
+	recordQuit             uint8 = 0
+	recordKey              uint8 = 1
+	recordMouseButton      uint8 = 2
+	recordMouseWheel       uint8 = 3
+	recordWindow           uint8 = 4
+	recordTextInput        uint8 = 5
+	recordTextEditing      uint8 = 6
+	recordMouseMotion      uint8 = 7
+	recordControllerDevice uint8 = 8
+	recordControllerButton uint8 = 9
+	recordControllerAxis   uint8 = 10
+	recordTouchFinger      uint8 = 11
+	recordMultiGesture     uint8 = 12
+)
+
+var (
+	recordingMagic       = [8]byte{'F', 'I', 'N', 'E', 'R', 'E', 'C', 1}
+	errInvalidRecording  = errors.New("invalid input recording")
+	errAlreadyRecording  = errors.New("already recording input")
+	errAlreadyReplaying  = errors.New("already replaying input")
+	errUnknownRecordKind = errors.New("unknown event in input recording")
+)
+
+type recordedKeyEvent struct {
+	Type     uint32
+	WindowID uint32
+	State    uint8
+	Repeat   uint8
+	Scancode uint32
+	Sym      int32
+	Mod      uint16
+}
+
+type recordedMouseButtonEvent struct {
+	Type     uint32
+	WindowID uint32
+	Which    uint32
+	Button   uint8
+	State    uint8
+	Clicks   uint8
+	X        int32
+	Y        int32
+}
+
+type recordedMouseMotionEvent struct {
+	Type     uint32
+	WindowID uint32
+	Which    uint32
+	State    uint32
+	X        int32
+	Y        int32
+	XRel     int32
+	YRel     int32
+}
+
+type recordedMouseWheelEvent struct {
+	Type      uint32
+	WindowID  uint32
+	Which     uint32
+	X         int32
+	Y         int32
+	Direction uint32
+	PreciseX  float32
+	PreciseY  float32
+}
+
+type recordedWindowEvent struct {
+	Type     uint32
+	WindowID uint32
+	Event    uint8
+	Data1    int32
+	Data2    int32
+}
+
+type recordedTextEvent struct {
+	Type     uint32
+	WindowID uint32
+	Text     [32]byte
+	Start    int32
+	Length   int32
+}
+
+// A gamepad connection or disconnection. Which is the instance ID of the
+// gamepad for both, unlike in SDL events.
+type recordedControllerDeviceEvent struct {
+	Type  uint32
+	Which int32
+}
+
+type recordedControllerButtonEvent struct {
+	Type   uint32
+	Which  int32
+	Button uint8
+	State  uint8
+}
+
+type recordedControllerAxisEvent struct {
+	Type  uint32
+	Which int32
+	Axis  uint8
+	Value int16
+}
+
+type recordedTouchFingerEvent struct {
+	Type     uint32
+	TouchID  int64
+	FingerID int64
+	X        float32
+	Y        float32
+	DX       float32
+	DY       float32
+	Pressure float32
+}
+
+type recordedMultiGestureEvent struct {
+	Type       uint32
+	TouchID    int64
+	DTheta     float32
+	DDist      float32
+	X          float32
+	Y          float32
+	NumFingers uint16
+}
+
+// A gamepad connection read from a recording. No device is opened, the
+// gamepad is only driven by the recorded events.
+type replayedGamepadEvent struct {
+	sdl.ControllerDeviceEvent
+}
+
+type inputRecorder struct {
+	writer *bufio.Writer
+	closer io.Closer
+	kinds  []uint8
+	events []interface{}
+}
+
+type inputReplayer struct {
+	reader        *bufio.Reader
+	closer        io.Closer
+	clock         *ManualClock
+	previousClock Clock
+}
+
+// Starts recording every input event processed by the app, together with the
+// delta time of every frame. Call app.StopRecording to finish the recording.
+func (app *App) StartRecording(writer io.Writer) error {
+	if app.recorder != nil {
+		return errAlreadyRecording
+	}
+
+	bufWriter := bufio.NewWriter(writer)
+	if _, err := bufWriter.Write(recordingMagic[:]); err != nil {
+		return err
+	}
+	app.recorder = &inputRecorder{writer: bufWriter}
+
+	// Record the starting mouse position, so the replay doesn't depend on
+	// where the mouse is when it starts
+	x, y := app.LogicalToWindow(int(app.MouseX), int(app.MouseY))
+	app.recorder.add(&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: int32(x), Y: int32(y)})
+	// Same for the gamepads that are already connected
+	for _, gamepad := range app.Gamepads {
+		app.recorder.push(recordControllerDevice, &recordedControllerDeviceEvent{
+			Type:  sdl.CONTROLLERDEVICEADDED,
+			Which: int32(gamepad.ID),
+		})
+	}
+	return nil
+}
+
+// Starts recording input to a file, see app.StartRecording.
+func (app *App) StartRecordingToFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := app.StartRecording(file); err != nil {
+		file.Close()
+		return err
+	}
+	app.recorder.closer = file
+	return nil
+}
+
+// Stops recording input and flushes the recording. If the recording was
+// started with app.StartRecordingToFile, the file is closed.
+func (app *App) StopRecording() error {
+	recorder := app.recorder
+	if recorder == nil {
+		return nil
+	}
+	app.recorder = nil
+
+	err := recorder.writer.Flush()
+	if recorder.closer != nil {
+		if closeErr := recorder.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Returns whether the app is recording input.
+func (app *App) IsRecording() bool {
+	return app.recorder != nil
+}
+
+// Starts replaying a recording made with app.StartRecording. While replaying,
+// the recorded events are fed into the app frame by frame and the app clock
+// advances by the recorded delta times. Input from the user is ignored until
+// the replay ends.
+func (app *App) StartReplay(reader io.Reader) error {
+	if app.replayer != nil {
+		return errAlreadyReplaying
+	}
+
+	bufReader := bufio.NewReader(reader)
+	var magic [8]byte
+	if _, err := io.ReadFull(bufReader, magic[:]); err != nil || magic != recordingMagic {
+		return errInvalidRecording
+	}
+
+	app.replayer = &inputReplayer{
+		reader:        bufReader,
+		clock:         NewManualClock().Set(app.Time),
+		previousClock: app.Clock,
+	}
+	app.Clock = app.replayer.clock
+	return nil
+}
+
+// Starts replaying a recording from a file, see app.StartReplay.
+func (app *App) StartReplayFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	if err := app.StartReplay(file); err != nil {
+		file.Close()
+		return err
+	}
+	app.replayer.closer = file
+	return nil
+}
+
+// Stops replaying input and restores the previous app clock.
+func (app *App) StopReplay() {
+	replayer := app.replayer
+	if replayer == nil {
+		return
+	}
+	app.replayer = nil
+
+	if replayer.closer != nil {
+		replayer.closer.Close()
+	}
+	app.Clock = replayer.previousClock
+	if app.Clock == nil {
+		app.Clock = NewRealClock()
+	}
+	app.PreviousFrameTime = app.Clock.Now()
+
+	if app.OnReplayEnd != nil {
+		app.OnReplayEnd(app)
+	}
+}
+
+// Returns whether the app is replaying input.
+func (app *App) IsReplaying() bool {
+	return app.replayer != nil
+}
+
+// Sets the replay end function (called when a replay has finished playing).
+func (app *App) SetReplayEndFunc(newFunc ReplayEndFunc) *App {
+	app.OnReplayEnd = newFunc
+	return app
+}
+
+// Stores an event that will be written when the frame ends. Events that
+// can't be replayed are ignored.
+func (recorder *inputRecorder) add(event sdl.Event) {
+	switch t := event.(type) {
+	case *sdl.QuitEvent:
+		recorder.push(recordQuit, &t.Type)
+	case *sdl.KeyboardEvent:
+		recorder.push(recordKey, &recordedKeyEvent{
+			Type:     t.Type,
+			WindowID: t.WindowID,
+			State:    t.State,
+			Repeat:   t.Repeat,
+			Scancode: uint32(t.Keysym.Scancode),
+			Sym:      int32(t.Keysym.Sym),
+			Mod:      t.Keysym.Mod,
+		})
+	case *sdl.MouseButtonEvent:
+		recorder.push(recordMouseButton, &recordedMouseButtonEvent{
+			Type:     t.Type,
+			WindowID: t.WindowID,
+			Which:    t.Which,
+			Button:   t.Button,
+			State:    t.State,
+			Clicks:   t.Clicks,
+			X:        t.X,
+			Y:        t.Y,
+		})
+	case *sdl.MouseMotionEvent:
+		recorder.push(recordMouseMotion, &recordedMouseMotionEvent{
+			Type:     t.Type,
+			WindowID: t.WindowID,
+			Which:    t.Which,
+			State:    t.State,
+			X:        t.X,
+			Y:        t.Y,
+			XRel:     t.XRel,
+			YRel:     t.YRel,
+		})
+	case *sdl.MouseWheelEvent:
+		recorder.push(recordMouseWheel, &recordedMouseWheelEvent{
+			Type:      t.Type,
+			WindowID:  t.WindowID,
+			Which:     t.Which,
+			X:         t.X,
+			Y:         t.Y,
+			Direction: t.Direction,
+			PreciseX:  t.PreciseX,
+			PreciseY:  t.PreciseY,
+		})
+	case *sdl.TextInputEvent:
+		r := &recordedTextEvent{Type: t.Type, WindowID: t.WindowID}
+		copy(r.Text[:], t.Text[:])
+		recorder.push(recordTextInput, r)
+	case *sdl.TextEditingEvent:
+		r := &recordedTextEvent{Type: t.Type, WindowID: t.WindowID, Start: t.Start, Length: t.Length}
+		copy(r.Text[:], t.Text[:])
+		recorder.push(recordTextEditing, r)
+	case *sdl.ControllerDeviceEvent:
+		which := int32(t.Which)
+		if t.Type == sdl.CONTROLLERDEVICEADDED {
+			// Which is a device index, which can differ when replaying
+			which = int32(sdl.JoystickGetDeviceInstanceID(int(t.Which)))
+		}
+		recorder.push(recordControllerDevice, &recordedControllerDeviceEvent{Type: t.Type, Which: which})
+	case *sdl.ControllerButtonEvent:
+		recorder.push(recordControllerButton, &recordedControllerButtonEvent{
+			Type:   t.Type,
+			Which:  int32(t.Which),
+			Button: t.Button,
+			State:  t.State,
+		})
+	case *sdl.ControllerAxisEvent:
+		recorder.push(recordControllerAxis, &recordedControllerAxisEvent{
+			Type:  t.Type,
+			Which: int32(t.Which),
+			Axis:  t.Axis,
+			Value: t.Value,
+		})
+	case *sdl.TouchFingerEvent:
+		recorder.push(recordTouchFinger, &recordedTouchFingerEvent{
+			Type:     t.Type,
+			TouchID:  int64(t.TouchID),
+			FingerID: int64(t.FingerID),
+			X:        t.X,
+			Y:        t.Y,
+			DX:       t.DX,
+			DY:       t.DY,
+			Pressure: t.Pressure,
+		})
+	case *sdl.MultiGestureEvent:
+		recorder.push(recordMultiGesture, &recordedMultiGestureEvent{
+			Type:       t.Type,
+			TouchID:    int64(t.TouchID),
+			DTheta:     t.DTheta,
+			DDist:      t.DDist,
+			X:          t.X,
+			Y:          t.Y,
+			NumFingers: t.NumFingers,
+		})
+	case *sdl.WindowEvent:
+		recorder.push(recordWindow, &recordedWindowEvent{
+			Type:     t.Type,
+			WindowID: t.WindowID,
+			Event:    t.Event,
+			Data1:    t.Data1,
+			Data2:    t.Data2,
+		})
+	}
+}
+
+func (recorder *inputRecorder) push(kind uint8, data interface{}) {
+	recorder.kinds = append(recorder.kinds, kind)
+	recorder.events = append(recorder.events, data)
+}
+
+// Writes the delta time and the events of a frame.
+func (recorder *inputRecorder) writeFrame(deltaTime float64) error {
+	defer func() {
+		recorder.kinds = recorder.kinds[:0]
+		recorder.events = recorder.events[:0]
+	}()
+
+	if err := binary.Write(recorder.writer, binary.LittleEndian, deltaTime); err != nil {
+		return err
+	}
+	var count [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(count[:], uint64(len(recorder.events)))
+	if _, err := recorder.writer.Write(count[:n]); err != nil {
+		return err
+	}
+
+	for idx, event := range recorder.events {
+		if err := recorder.writer.WriteByte(recorder.kinds[idx]); err != nil {
+			return err
+		}
+		if err := binary.Write(recorder.writer, binary.LittleEndian, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads the next frame of the recording. Returns the delta time of the frame
+// and its events, or io.EOF if the recording has ended.
+func (replayer *inputReplayer) readFrame() (float64, []sdl.Event, error) {
+	var deltaTime float64
+	if err := binary.Read(replayer.reader, binary.LittleEndian, &deltaTime); err != nil {
+		return 0, nil, err
+	}
+	count, err := binary.ReadUvarint(replayer.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	events := make([]sdl.Event, 0, count)
+	for i := uint64(0); i < count; i++ {
+		kind, err := replayer.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		event, err := replayer.readEvent(kind)
+		if err != nil {
+			return 0, nil, err
+		}
+		events = append(events, event)
+	}
+	return deltaTime, events, nil
+}
+
+func (replayer *inputReplayer) readEvent(kind uint8) (sdl.Event, error) {
+	order := binary.LittleEndian
+
+	switch kind {
+	case recordQuit:
+		event := &sdl.QuitEvent{}
+		err := binary.Read(replayer.reader, order, &event.Type)
+		return event, err
+	case recordKey:
+		var r recordedKeyEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.KeyboardEvent{
+			Type:     r.Type,
+			WindowID: r.WindowID,
+			State:    r.State,
+			Repeat:   r.Repeat,
+			Keysym: sdl.Keysym{
+				Scancode: sdl.Scancode(r.Scancode),
+				Sym:      sdl.Keycode(r.Sym),
+				Mod:      r.Mod,
+			},
+		}, err
+	case recordMouseButton:
+		var r recordedMouseButtonEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.MouseButtonEvent{
+			Type:     r.Type,
+			WindowID: r.WindowID,
+			Which:    r.Which,
+			Button:   r.Button,
+			State:    r.State,
+			Clicks:   r.Clicks,
+			X:        r.X,
+			Y:        r.Y,
+		}, err
+	case recordMouseMotion:
+		var r recordedMouseMotionEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.MouseMotionEvent{
+			Type:     r.Type,
+			WindowID: r.WindowID,
+			Which:    r.Which,
+			State:    r.State,
+			X:        r.X,
+			Y:        r.Y,
+			XRel:     r.XRel,
+			YRel:     r.YRel,
+		}, err
+	case recordMouseWheel:
+		var r recordedMouseWheelEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.MouseWheelEvent{
+			Type:      r.Type,
+			WindowID:  r.WindowID,
+			Which:     r.Which,
+			X:         r.X,
+			Y:         r.Y,
+			Direction: r.Direction,
+			PreciseX:  r.PreciseX,
+			PreciseY:  r.PreciseY,
+		}, err
+	case recordWindow:
+		var r recordedWindowEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.WindowEvent{
+			Type:     r.Type,
+			WindowID: r.WindowID,
+			Event:    r.Event,
+			Data1:    r.Data1,
+			Data2:    r.Data2,
+		}, err
+	case recordTextInput:
+		var r recordedTextEvent
+		err := binary.Read(replayer.reader, order, &r)
+		event := &sdl.TextInputEvent{Type: r.Type, WindowID: r.WindowID}
+		copy(event.Text[:], r.Text[:])
+		return event, err
+	case recordTextEditing:
+		var r recordedTextEvent
+		err := binary.Read(replayer.reader, order, &r)
+		event := &sdl.TextEditingEvent{Type: r.Type, WindowID: r.WindowID, Start: r.Start, Length: r.Length}
+		copy(event.Text[:], r.Text[:])
+		return event, err
+	case recordControllerDevice:
+		var r recordedControllerDeviceEvent
+		err := binary.Read(replayer.reader, order, &r)
+		event := sdl.ControllerDeviceEvent{Type: r.Type, Which: sdl.JoystickID(r.Which)}
+		if r.Type == sdl.CONTROLLERDEVICEADDED {
+			return &replayedGamepadEvent{event}, err
+		}
+		return &event, err
+	case recordControllerButton:
+		var r recordedControllerButtonEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.ControllerButtonEvent{
+			Type:   r.Type,
+			Which:  sdl.JoystickID(r.Which),
+			Button: r.Button,
+			State:  r.State,
+		}, err
+	case recordControllerAxis:
+		var r recordedControllerAxisEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.ControllerAxisEvent{
+			Type:  r.Type,
+			Which: sdl.JoystickID(r.Which),
+			Axis:  r.Axis,
+			Value: r.Value,
+		}, err
+	case recordTouchFinger:
+		var r recordedTouchFingerEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.TouchFingerEvent{
+			Type:     r.Type,
+			TouchID:  sdl.TouchID(r.TouchID),
+			FingerID: sdl.FingerID(r.FingerID),
+			X:        r.X,
+			Y:        r.Y,
+			DX:       r.DX,
+			DY:       r.DY,
+			Pressure: r.Pressure,
+		}, err
+	case recordMultiGesture:
+		var r recordedMultiGestureEvent
+		err := binary.Read(replayer.reader, order, &r)
+		return &sdl.MultiGestureEvent{
+			Type:       r.Type,
+			TouchID:    sdl.TouchID(r.TouchID),
+			DTheta:     r.DTheta,
+			DDist:      r.DDist,
+			X:          r.X,
+			Y:          r.Y,
+			NumFingers: r.NumFingers,
+		}, err
+	}
+	return nil, fmt.Errorf("%w: %d", errUnknownRecordKind, kind)
+}
+
+// Feeds the next recorded frame into the app. Stops the replay when the
+// recording has ended.
+func (app *App) replayFrame() {
+	deltaTime, events, err := app.replayer.readFrame()
+	if err != nil {
+		// The recording has ended (or is truncated)
+		app.StopReplay()
+		return
+	}
+
+	for _, event := range events {
+		app.handleEvent(event)
+	}
+	app.replayer.clock.Advance(deltaTime)
+}
@@ -0,0 +1,160 @@
+package fine
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Records two frames: one with input events and one without.
+func testRecording(t *testing.T) []byte {
+	t.Helper()
+	app := &App{MouseX: 10, MouseY: 20, Gamepads: []*Gamepad{{ID: 4}}}
+	var buf bytes.Buffer
+	if err := app.StartRecording(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	app.recorder.add(&sdl.KeyboardEvent{Type: sdl.KEYDOWN, State: sdl.PRESSED, Keysym: sdl.Keysym{Scancode: sdl.SCANCODE_A, Sym: sdl.K_a}})
+	app.recorder.add(&sdl.ControllerButtonEvent{Type: sdl.CONTROLLERBUTTONDOWN, Which: 4, Button: sdl.CONTROLLER_BUTTON_A, State: sdl.PRESSED})
+	app.recorder.add(&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 1, PreciseY: 1.5})
+	if err := app.recorder.writeFrame(0.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.recorder.writeFrame(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	app := &App{}
+	if err := app.StartReplay(bytes.NewReader(testRecording(t))); err != nil {
+		t.Fatalf("StartReplay: %s", err)
+	}
+
+	deltaTime, events, err := app.replayer.readFrame()
+	if err != nil {
+		t.Fatalf("reading the first frame: %s", err)
+	}
+	want := []sdl.Event{
+		&sdl.MouseMotionEvent{Type: sdl.MOUSEMOTION, X: 10, Y: 20},
+		&replayedGamepadEvent{sdl.ControllerDeviceEvent{Type: sdl.CONTROLLERDEVICEADDED, Which: 4}},
+		&sdl.KeyboardEvent{Type: sdl.KEYDOWN, State: sdl.PRESSED, Keysym: sdl.Keysym{Scancode: sdl.SCANCODE_A, Sym: sdl.K_a}},
+		&sdl.ControllerButtonEvent{Type: sdl.CONTROLLERBUTTONDOWN, Which: 4, Button: sdl.CONTROLLER_BUTTON_A, State: sdl.PRESSED},
+		&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 1, PreciseY: 1.5},
+	}
+	if deltaTime != 0.5 || !reflect.DeepEqual(events, want) {
+		t.Errorf("first frame = %v, %+v, want 0.5, %+v", deltaTime, events, want)
+	}
+
+	deltaTime, events, err = app.replayer.readFrame()
+	if err != nil || deltaTime != 0.25 || len(events) != 0 {
+		t.Errorf("second frame = %v, %v, %v, want 0.25 without events", deltaTime, events, err)
+	}
+	if _, _, err := app.replayer.readFrame(); err != io.EOF {
+		t.Errorf("reading after the last frame = %v, want io.EOF", err)
+	}
+}
+
+func TestReplayFrames(t *testing.T) {
+	app := &App{Time: 3}
+	previousClock := NewManualClock()
+	app.Clock = previousClock
+	ended := 0
+	app.SetReplayEndFunc(func(app *App) {
+		ended++
+	})
+	if err := app.StartReplay(bytes.NewReader(testRecording(t))); err != nil {
+		t.Fatalf("StartReplay: %s", err)
+	}
+
+	app.replayFrame()
+	if now := app.Clock.Now(); now != 3.5 {
+		t.Errorf("clock = %v after the first frame, want 3.5", now)
+	}
+	gamepad := app.GetGamepad(4)
+	if gamepad == nil || !gamepad.IsButtonDown(GAMEPAD_BUTTON_A) {
+		t.Errorf("replayed gamepad = %+v, want A held", gamepad)
+	}
+	if app.MouseX != 10 || app.MouseY != 20 {
+		t.Errorf("mouse = %d, %d, want 10, 20", app.MouseX, app.MouseY)
+	}
+
+	app.replayFrame()
+	app.replayFrame()
+	if app.IsReplaying() || ended != 1 || app.Clock != Clock(previousClock) {
+		t.Errorf("replaying = %v, ended %d times, want the replay ended once with the clock restored", app.IsReplaying(), ended)
+	}
+}
+
+func TestStartReplayInvalidHeader(t *testing.T) {
+	recording := testRecording(t)
+	otherVersion := append([]byte(nil), recording...)
+	otherVersion[7]++
+
+	for name, data := range map[string][]byte{
+		"empty":         nil,
+		"short header":  recording[:5],
+		"wrong magic":   append([]byte("NOTFINE!"), recording[8:]...),
+		"other version": otherVersion,
+	} {
+		app := &App{}
+		if err := app.StartReplay(bytes.NewReader(data)); err != errInvalidRecording {
+			t.Errorf("%s: StartReplay = %v, want errInvalidRecording", name, err)
+		}
+		if app.IsReplaying() {
+			t.Errorf("%s: replay started", name)
+		}
+	}
+
+	app := &App{}
+	if err := app.StartReplay(bytes.NewReader(recording)); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.StartReplay(bytes.NewReader(recording)); err != errAlreadyReplaying {
+		t.Errorf("second StartReplay = %v, want errAlreadyReplaying", err)
+	}
+}
+
+func TestReplayTruncated(t *testing.T) {
+	recording := testRecording(t)
+
+	// Cut the recording inside the last event of the first frame
+	app := &App{}
+	if err := app.StartReplay(bytes.NewReader(recording[:len(recording)-20])); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := app.replayer.readFrame(); err != io.ErrUnexpectedEOF {
+		t.Errorf("reading a truncated frame = %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	// The partial frame is not replayed
+	app = &App{}
+	if err := app.StartReplay(bytes.NewReader(recording[:len(recording)-20])); err != nil {
+		t.Fatal(err)
+	}
+	app.replayFrame()
+	if app.IsReplaying() || len(app.Gamepads) != 0 {
+		t.Errorf("replaying = %v, gamepads = %v, want the replay stopped before any event", app.IsReplaying(), app.Gamepads)
+	}
+
+	// Unknown events are reported
+	unknown := append([]byte(nil), recordingMagic[:]...)
+	unknown = append(unknown, make([]byte, 8)...)
+	unknown = append(unknown, 1, 200)
+	app = &App{}
+	if err := app.StartReplay(bytes.NewReader(unknown)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := app.replayer.readFrame(); !errors.Is(err, errUnknownRecordKind) {
+		t.Errorf("reading an unknown event = %v, want errUnknownRecordKind", err)
+	}
+}
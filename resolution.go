@@ -0,0 +1,163 @@
+package fine
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// How the logical resolution is scaled to fit the window.
+type ScaleMode int
+
+const (
+	SCALE_LETTERBOX ScaleMode = iota // Scale as much as possible while keeping the aspect ratio, with bars on the sides.
+	SCALE_STRETCH                    // Stretch to fill the whole window, ignoring the aspect ratio.
+	SCALE_INTEGER                    // Scale by the largest whole number that fits, for sharp pixel art.
+)
+
+// Sets the logical render size. Everything is drawn at this resolution and
+// scaled to the window according to app.ScaleMode, so resizing the window
+// doesn't change what is visible. Set the size to 0, 0 to render at the
+// resolution of the window.
+func (app *App) SetLogicalSize(width, height int32) *App {
+	app.LogicalWidth, app.LogicalHeight = width, height
+	app.scalingChanged = true
+	return app
+}
+
+// Sets how the logical resolution is scaled to fit the window.
+func (app *App) SetScaleMode(mode ScaleMode) *App {
+	app.ScaleMode = mode
+	app.scalingChanged = true
+	return app
+}
+
+// Returns the logical render size, or 0, 0 if the app renders at the
+// resolution of the window.
+func (app *App) GetLogicalSize() (int32, int32) {
+	if !app.hasLogicalSize() {
+		return 0, 0
+	}
+	return app.LogicalWidth, app.LogicalHeight
+}
+
+// Returns the size of the drawable area of the window in pixels. This is
+// larger than the window size on HiDPI displays.
+func (app *App) GetDrawableSize() (int32, int32) {
+	return app.DrawableWidth, app.DrawableHeight
+}
+
+func (app *App) hasLogicalSize() bool {
+	return app.LogicalWidth > 0 && app.LogicalHeight > 0
+}
+
+// Returns the offset of the rendered area in pixels and the scale from render
+// coordinates to pixels.
+func (app *App) renderTransform() (offsetX, offsetY, scaleX, scaleY float64) {
+	if !app.hasLogicalSize() || app.DrawableWidth <= 0 || app.DrawableHeight <= 0 {
+		return 0, 0, 1, 1
+	}
+
+	drawW, drawH := float64(app.DrawableWidth), float64(app.DrawableHeight)
+	logicalW, logicalH := float64(app.LogicalWidth), float64(app.LogicalHeight)
+
+	switch app.ScaleMode {
+	case SCALE_STRETCH:
+		return 0, 0, drawW / logicalW, drawH / logicalH
+	case SCALE_INTEGER:
+		scaleX = math.Max(math.Floor(math.Min(drawW/logicalW, drawH/logicalH)), 1)
+	default:
+		scaleX = math.Min(drawW/logicalW, drawH/logicalH)
+	}
+	scaleY = scaleX
+
+	offsetX = math.Floor((drawW - logicalW*scaleX) / 2)
+	offsetY = math.Floor((drawH - logicalH*scaleY) / 2)
+	return offsetX, offsetY, scaleX, scaleY
+}
+
+// Converts a position in window coordinates (like the mouse position) to
+// render coordinates.
+func (app *App) WindowToLogical(x, y int) (int, int) {
+	lx, ly := app.windowToLogical(float64(x), float64(y))
+	return int(math.Floor(lx)), int(math.Floor(ly))
+}
+
+// Converts a position in render coordinates to window coordinates.
+func (app *App) LogicalToWindow(x, y int) (int, int) {
+	offsetX, offsetY, scaleX, scaleY := app.renderTransform()
+	dpiX, dpiY := app.dpiScale()
+	wx := (float64(x)*scaleX + offsetX) / dpiX
+	wy := (float64(y)*scaleY + offsetY) / dpiY
+	return int(math.Round(wx)), int(math.Round(wy))
+}
+
+func (app *App) windowToLogical(x, y float64) (float64, float64) {
+	offsetX, offsetY, scaleX, scaleY := app.renderTransform()
+	dpiX, dpiY := app.dpiScale()
+	return (x*dpiX - offsetX) / scaleX, (y*dpiY - offsetY) / scaleY
+}
+
+// Converts a movement in window coordinates to render coordinates.
+func (app *App) windowToLogicalDelta(dx, dy int32) (float64, float64) {
+	_, _, scaleX, scaleY := app.renderTransform()
+	dpiX, dpiY := app.dpiScale()
+	return float64(dx) * dpiX / scaleX, float64(dy) * dpiY / scaleY
+}
+
+// Returns the amount of pixels per window coordinate.
+func (app *App) dpiScale() (float64, float64) {
+	if app.WindowWidth <= 0 || app.WindowHeight <= 0 || app.DrawableWidth <= 0 || app.DrawableHeight <= 0 {
+		return 1, 1
+	}
+	return float64(app.DrawableWidth) / float64(app.WindowWidth),
+		float64(app.DrawableHeight) / float64(app.WindowHeight)
+}
+
+// Updates the window, drawable and render sizes and applies the scaling if
+// it has changed.
+func (app *App) updateRenderSize() {
+	windowW, windowH := app.Window.GetSize()
+	drawW, drawH, err := app.Renderer.GetOutputSize()
+	if err != nil {
+		drawW, drawH = windowW, windowH
+	}
+	if windowW != app.WindowWidth || windowH != app.WindowHeight ||
+		drawW != app.DrawableWidth || drawH != app.DrawableHeight {
+		app.scalingChanged = true
+	}
+	app.WindowWidth, app.WindowHeight = windowW, windowH
+	app.DrawableWidth, app.DrawableHeight = drawW, drawH
+
+	if app.hasLogicalSize() {
+		app.Width, app.Height = app.LogicalWidth, app.LogicalHeight
+	} else {
+		app.Width, app.Height = drawW, drawH
+	}
+
+	if app.scalingChanged {
+		app.applyScaling()
+	}
+}
+
+// Sets the scale and viewport of the renderer. SDL's own logical size is not
+// used, because it can't stretch and only maps some of the mouse events.
+func (app *App) applyScaling() {
+	app.scalingChanged = false
+	if !app.hasLogicalSize() {
+		app.Renderer.SetScale(1, 1)
+		app.Renderer.SetViewport(nil)
+		return
+	}
+
+	offsetX, offsetY, scaleX, scaleY := app.renderTransform()
+	app.Renderer.SetScale(float32(scaleX), float32(scaleY))
+
+	// The viewport is scaled by the renderer scale
+	app.Renderer.SetViewport(&sdl.Rect{
+		X: int32(math.Round(offsetX / scaleX)),
+		Y: int32(math.Round(offsetY / scaleY)),
+		W: app.LogicalWidth,
+		H: app.LogicalHeight,
+	})
+}
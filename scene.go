@@ -1,5 +1,258 @@
 package fine
 
+import (
+	"errors"
+	"fmt"
+)
+
+type SceneFunc func(scene *Scene, app *App) // Function that is called when the state of a scene changes.
+
+var (
+	errLastScene error = errors.New("cannot pop the last scene")
+)
+
 type Scene struct {
-	Entities []*Entity // All entities on the scene.
+	Name           string    // The name of the scene, used by app.PushScene and others.
+	Entities       []*Entity // All entities on the scene.
+	Events         *EventBus // Events of this scene, see scene.GetEvents.
+	OnEnter        SceneFunc // Function that is called every time the scene becomes the active scene (on every push and replace). Create the entities of the scene once with scene.Add instead.
+	OnExit         SceneFunc // Function that is called when the scene is popped or replaced.
+	OnPause        SceneFunc // Function that is called when another scene is pushed on top of this scene.
+	OnResume       SceneFunc // Function that is called when the scene on top of this scene is popped.
+	DrawWhenPaused bool      // Keep drawing this scene (without updating it) while another scene is on top of it.
+	Layers         []*Layer  // Render layers of the scene.
+	drawOrder      drawOrder
+}
+
+type sceneChange struct {
+	transition Transition
+	apply      func()
+	elapsed    float64
+	applied    bool
+}
+
+// Creates a new empty scene.
+func NewScene() *Scene {
+	return &Scene{Events: NewEventBus()}
+}
+
+// Returns the event bus of the scene. The bus is created if the scene doesn't
+// have one yet (if it wasn't created with NewScene).
+func (scene *Scene) GetEvents() *EventBus {
+	if scene.Events == nil {
+		scene.Events = NewEventBus()
+	}
+	return scene.Events
+}
+
+// Creates a new named scene and adds it to the app, so it can be pushed with
+// app.PushScene.
+func (app *App) AddScene(name string) *Scene {
+	scene := NewScene()
+	scene.Name = name
+	app.Scenes[name] = scene
+	return scene
+}
+
+// Returns a scene added with app.AddScene, or nil if it doesn't exist.
+func (app *App) GetScene(name string) *Scene {
+	return app.Scenes[name]
+}
+
+// Removes a scene from the app. Scenes on the stack are not affected.
+func (app *App) RemoveScene(name string) {
+	delete(app.Scenes, name)
+}
+
+// Moves an entity and its children to this scene, removing them from their
+// previous scene. Use it to fill a scene before it is pushed, for example
+// scene.Add(app.Entity(position)).
+func (scene *Scene) Add(entity *Entity) *Entity {
+	if entity.Scene != scene {
+		entity.removeFromScene()
+		entity.Scene = scene
+		scene.Entities = append(scene.Entities, entity)
+	}
+	for _, child := range entity.Children {
+		scene.Add(child)
+	}
+	return entity
+}
+
+// Sets the callback called every time the scene becomes the active scene.
+func (scene *Scene) SetEnterFunc(newFunc SceneFunc) *Scene {
+	scene.OnEnter = newFunc
+	return scene
+}
+
+// Sets the callback called when the scene is popped or replaced.
+func (scene *Scene) SetExitFunc(newFunc SceneFunc) *Scene {
+	scene.OnExit = newFunc
+	return scene
+}
+
+// Sets the callback called when another scene is pushed on top of this scene.
+func (scene *Scene) SetPauseFunc(newFunc SceneFunc) *Scene {
+	scene.OnPause = newFunc
+	return scene
+}
+
+// Sets the callback called when the scene on top of this scene is popped.
+func (scene *Scene) SetResumeFunc(newFunc SceneFunc) *Scene {
+	scene.OnResume = newFunc
+	return scene
+}
+
+// Specifies if the scene should be drawn while another scene is on top of it.
+func (scene *Scene) SetDrawWhenPaused(draw bool) *Scene {
+	scene.DrawWhenPaused = draw
+	return scene
+}
+
+func (app *App) findScene(name string) (*Scene, error) {
+	scene, ok := app.Scenes[name]
+	if !ok {
+		return nil, fmt.Errorf("scene %q does not exist", name)
+	}
+	return scene, nil
+}
+
+// Returns the scene stack. The last scene is always app.Scene, even if
+// app.Scene was assigned directly.
+func (app *App) sceneStack() []*Scene {
+	if len(app.SceneStack) == 0 {
+		app.SceneStack = []*Scene{app.Scene}
+	} else if app.SceneStack[len(app.SceneStack)-1] != app.Scene {
+		app.SceneStack[len(app.SceneStack)-1] = app.Scene
+	}
+	return app.SceneStack
+}
+
+// Pushes a scene on top of the scene stack, pausing the current scene. The
+// change happens at the start of the next frame, or in the middle of the
+// transition if one is given.
+func (app *App) PushScene(name string, transition Transition) error {
+	scene, err := app.findScene(name)
+	if err != nil {
+		return err
+	}
+	app.queueSceneChange(transition, func() {
+		previous := app.Scene
+		app.SceneStack = append(app.sceneStack(), scene)
+		app.Scene = scene
+		if previous.OnPause != nil {
+			previous.OnPause(previous, app)
+		}
+		if scene.OnEnter != nil {
+			scene.OnEnter(scene, app)
+		}
+	})
+	return nil
+}
+
+// Pops the current scene from the scene stack and resumes the scene under it.
+// The change happens at the start of the next frame, or in the middle of the
+// transition if one is given.
+func (app *App) PopScene(transition Transition) error {
+	pending := len(app.sceneChanges) > 0 || app.sceneTransition != nil
+	if !pending && len(app.sceneStack()) <= 1 {
+		return errLastScene
+	}
+	app.queueSceneChange(transition, func() {
+		stack := app.sceneStack()
+		if len(stack) <= 1 {
+			// Queued changes have removed the other scenes
+			return
+		}
+		popped := stack[len(stack)-1]
+		app.SceneStack = stack[:len(stack)-1]
+		app.Scene = app.SceneStack[len(app.SceneStack)-1]
+		if popped.OnExit != nil {
+			popped.OnExit(popped, app)
+		}
+		if app.Scene.OnResume != nil {
+			app.Scene.OnResume(app.Scene, app)
+		}
+	})
+	return nil
+}
+
+// Replaces the current scene with another scene. The change happens at the
+// start of the next frame, or in the middle of the transition if one is given.
+func (app *App) ReplaceScene(name string, transition Transition) error {
+	scene, err := app.findScene(name)
+	if err != nil {
+		return err
+	}
+	app.queueSceneChange(transition, func() {
+		stack := app.sceneStack()
+		previous := stack[len(stack)-1]
+		stack[len(stack)-1] = scene
+		app.Scene = scene
+		if previous.OnExit != nil {
+			previous.OnExit(previous, app)
+		}
+		if scene.OnEnter != nil {
+			scene.OnEnter(scene, app)
+		}
+	})
+	return nil
+}
+
+// Checks if a scene transition is in progress.
+func (app *App) IsSceneTransitioning() bool {
+	return app.sceneTransition != nil
+}
+
+func (app *App) queueSceneChange(transition Transition, apply func()) {
+	app.sceneChanges = append(app.sceneChanges, &sceneChange{transition: transition, apply: apply})
+}
+
+// Applies the queued scene changes and advances the current transition.
+func (app *App) updateScenes() {
+	if change := app.sceneTransition; change != nil {
+		change.elapsed += app.DeltaTime
+		length := change.transition.Length()
+		if !change.applied && change.elapsed >= length/2 {
+			change.apply()
+			change.applied = true
+		}
+		if change.elapsed >= length {
+			app.sceneTransition = nil
+		}
+	}
+
+	for app.sceneTransition == nil && len(app.sceneChanges) > 0 {
+		change := app.sceneChanges[0]
+		app.sceneChanges = app.sceneChanges[1:]
+		if change.transition == nil || change.transition.Length() <= 0 {
+			change.apply()
+			continue
+		}
+		app.sceneTransition = change
+	}
+}
+
+// Returns the paused scenes that should be drawn under the active scene, from
+// the bottom to the top.
+func (app *App) visiblePausedScenes() []*Scene {
+	stack := app.sceneStack()
+	bottom := len(stack) - 1
+	for bottom > 0 && stack[bottom-1].DrawWhenPaused {
+		bottom--
+	}
+	return stack[bottom : len(stack)-1]
+}
+
+// Draws the current scene transition over the frame.
+func (app *App) drawSceneTransition() {
+	change := app.sceneTransition
+	if change == nil {
+		return
+	}
+	progress := change.elapsed / change.transition.Length()
+	if progress > 1 {
+		progress = 1
+	}
+	change.transition.Draw(progress, app)
 }
@@ -0,0 +1,156 @@
+package fine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Records the scene callbacks as "scene:callback".
+func newTestSceneApp(calls *[]string, names ...string) *App {
+	app := &App{Scene: NewScene(), Scenes: make(map[string]*Scene)}
+	app.Scene.Name = "main"
+	record := func(callback string) SceneFunc {
+		return func(scene *Scene, app *App) {
+			*calls = append(*calls, scene.Name+":"+callback)
+		}
+	}
+	for _, scene := range append([]*Scene{app.Scene}, addScenes(app, names)...) {
+		scene.SetEnterFunc(record("enter")).SetExitFunc(record("exit"))
+		scene.SetPauseFunc(record("pause")).SetResumeFunc(record("resume"))
+	}
+	return app
+}
+
+func addScenes(app *App, names []string) []*Scene {
+	var scenes []*Scene
+	for _, name := range names {
+		scenes = append(scenes, app.AddScene(name))
+	}
+	return scenes
+}
+
+func sceneNames(scenes []*Scene) []string {
+	var result []string
+	for _, scene := range scenes {
+		result = append(result, scene.Name)
+	}
+	return result
+}
+
+type testTransition struct {
+	length float64
+}
+
+func (transition testTransition) Length() float64 {
+	return transition.length
+}
+
+func (transition testTransition) Draw(progress float64, app *App) {}
+
+func TestPushAndPopScenes(t *testing.T) {
+	var calls []string
+	app := newTestSceneApp(&calls, "pause", "options")
+
+	app.PushScene("pause", nil)
+	app.PushScene("options", nil)
+	if len(calls) != 0 {
+		t.Errorf("scenes changed before the next frame: %v", calls)
+	}
+	app.updateScenes()
+	if got := sceneNames(app.SceneStack); !reflect.DeepEqual(got, []string{"main", "pause", "options"}) || app.Scene.Name != "options" {
+		t.Errorf("scene stack = %v, want [main pause options]", got)
+	}
+
+	app.PopScene(nil)
+	app.PopScene(nil)
+	app.updateScenes()
+	if app.Scene.Name != "main" || len(app.SceneStack) != 1 {
+		t.Errorf("scene = %s, stack = %v, want main only", app.Scene.Name, sceneNames(app.SceneStack))
+	}
+
+	want := []string{
+		"main:pause", "pause:enter", "pause:pause", "options:enter",
+		"options:exit", "pause:resume", "pause:exit", "main:resume",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestReplaceScene(t *testing.T) {
+	var calls []string
+	app := newTestSceneApp(&calls, "pause", "level")
+
+	app.PushScene("pause", nil)
+	app.ReplaceScene("level", nil)
+	app.updateScenes()
+	if got := sceneNames(app.SceneStack); !reflect.DeepEqual(got, []string{"main", "level"}) {
+		t.Errorf("scene stack = %v, want [main level]", got)
+	}
+	want := []string{"main:pause", "pause:enter", "pause:exit", "level:enter"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSceneErrors(t *testing.T) {
+	var calls []string
+	app := newTestSceneApp(&calls, "pause")
+
+	if err := app.PopScene(nil); err != errLastScene {
+		t.Errorf("PopScene on the last scene = %v, want errLastScene", err)
+	}
+	if err := app.PushScene("missing", nil); err == nil {
+		t.Error("pushed a scene that doesn't exist")
+	}
+
+	// A pop queued after a push is allowed, even if the stack is still empty
+	app.PushScene("pause", nil)
+	if err := app.PopScene(nil); err != nil {
+		t.Errorf("PopScene after a queued push = %v", err)
+	}
+	app.updateScenes()
+	if app.Scene.Name != "main" {
+		t.Errorf("scene = %s, want main", app.Scene.Name)
+	}
+}
+
+func TestSceneTransition(t *testing.T) {
+	var calls []string
+	app := newTestSceneApp(&calls, "pause")
+	app.PushScene("pause", testTransition{length: 1})
+
+	app.DeltaTime = 0.25
+	app.updateScenes()
+	if !app.IsSceneTransitioning() || app.Scene.Name != "main" {
+		t.Errorf("transitioning: %v, scene = %s, want the transition started on main", app.IsSceneTransitioning(), app.Scene.Name)
+	}
+	app.updateScenes()
+	app.updateScenes()
+	// The scene changes halfway through the transition
+	if !app.IsSceneTransitioning() || app.Scene.Name != "pause" {
+		t.Errorf("transitioning: %v, scene = %s, want the transition halfway on pause", app.IsSceneTransitioning(), app.Scene.Name)
+	}
+	app.updateScenes()
+	app.updateScenes()
+	if app.IsSceneTransitioning() {
+		t.Error("the transition didn't end")
+	}
+}
+
+func TestVisiblePausedScenes(t *testing.T) {
+	var calls []string
+	app := newTestSceneApp(&calls, "level", "inventory", "pause")
+	app.GetScene("level").SetDrawWhenPaused(true)
+	app.GetScene("inventory").SetDrawWhenPaused(true)
+
+	app.PushScene("level", nil)
+	app.PushScene("inventory", nil)
+	app.PushScene("pause", nil)
+	app.updateScenes()
+
+	// The main scene isn't drawn when paused
+	if got := sceneNames(app.visiblePausedScenes()); !reflect.DeepEqual(got, []string{"level", "inventory"}) {
+		t.Errorf("visible paused scenes = %v, want [level inventory]", got)
+	}
+}
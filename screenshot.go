@@ -0,0 +1,168 @@
+package fine
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Reads back the pixels drawn on the current frame. Call this before the frame
+// is presented (for example, in the post render function), the contents of the
+// renderer are undefined after presenting.
+func (app *App) Screenshot() (*image.RGBA, error) {
+	if !app.Running || app.Renderer == nil {
+		return nil, fmt.Errorf("renderer is not initialized, cannot take a screenshot")
+	}
+
+	w, h, err := app.Renderer.GetOutputSize()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, int(w), int(h)))
+	if len(img.Pix) == 0 {
+		return img, nil
+	}
+	// ABGR8888 is stored as R, G, B, A bytes on little endian systems, which is what image.RGBA uses
+	format := uint32(sdl.PIXELFORMAT_ABGR8888)
+	if sdl.BYTEORDER == sdl.BIG_ENDIAN {
+		format = sdl.PIXELFORMAT_RGBA8888
+	}
+
+	// Read the whole window, not just the logical viewport
+	if app.hasLogicalSize() {
+		app.Renderer.SetScale(1, 1)
+		app.Renderer.SetViewport(nil)
+		defer app.applyScaling()
+	}
+	if err := app.Renderer.ReadPixels(nil, format, unsafe.Pointer(&img.Pix[0]), img.Stride); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// Takes a screenshot of the current frame and saves it as a PNG file.
+func (app *App) SavePNG(path string) error {
+	img, err := app.Screenshot()
+	if err != nil {
+		return err
+	}
+	return SavePNG(img, path)
+}
+
+// Saves an image as a PNG file.
+func SavePNG(img image.Image, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Loads a PNG file.
+func LoadPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+// Takes a screenshot of the current frame and compares it against a golden
+// PNG file, see CompareGolden.
+func (app *App) CompareGolden(path string, tolerance uint8) error {
+	img, err := app.Screenshot()
+	if err != nil {
+		return err
+	}
+	return CompareGolden(img, path, tolerance)
+}
+
+// Compares an image against a golden PNG file. Pixels match if none of their
+// channels differ by more than the tolerance. If the images don't match, an
+// image highlighting the different pixels in red is written next to the golden
+// file (with a "_diff.png" suffix) and an error is returned.
+//
+// If the FINE_UPDATE_GOLDEN environment variable is set, the golden file is
+// overwritten with the image instead.
+func CompareGolden(img image.Image, path string, tolerance uint8) error {
+	if os.Getenv("FINE_UPDATE_GOLDEN") != "" {
+		return SavePNG(img, path)
+	}
+
+	golden, err := LoadPNG(path)
+	if err != nil {
+		return err
+	}
+	if !img.Bounds().Size().Eq(golden.Bounds().Size()) {
+		return fmt.Errorf(
+			"image size %v doesn't match golden image size %v (%s)",
+			img.Bounds().Size(), golden.Bounds().Size(), path,
+		)
+	}
+
+	actual, expected := toRGBA(img), toRGBA(golden)
+	diff := image.NewRGBA(actual.Bounds())
+	different := 0
+
+	for i := 0; i < len(actual.Pix); i += 4 {
+		mismatch := false
+		for c := 0; c < 4; c++ {
+			if absDiff(actual.Pix[i+c], expected.Pix[i+c]) > tolerance {
+				mismatch = true
+				break
+			}
+		}
+
+		if mismatch {
+			different++
+			copy(diff.Pix[i:i+4], []uint8{255, 0, 0, 255})
+		} else {
+			// Dim the matching pixels so the differences stand out
+			gray := uint8((uint16(actual.Pix[i]) + uint16(actual.Pix[i+1]) + uint16(actual.Pix[i+2])) / 3 / 4)
+			copy(diff.Pix[i:i+4], []uint8{gray, gray, gray, 255})
+		}
+	}
+
+	if different == 0 {
+		return nil
+	}
+
+	diffPath := strings.TrimSuffix(path, ".png") + "_diff.png"
+	if err := SavePNG(diff, diffPath); err != nil {
+		return err
+	}
+	return fmt.Errorf(
+		"%d of %d pixels differ from the golden image %s (diff: %s)",
+		different, len(actual.Pix)/4, path, diffPath,
+	)
+}
+
+// Converts any image to an image.RGBA starting at 0,0.
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	if rgba, ok := img.(*image.RGBA); ok && bounds.Min == (image.Point{}) && rgba.Stride == bounds.Dx()*4 {
+		return rgba
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
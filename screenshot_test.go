@@ -0,0 +1,138 @@
+package fine
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func solidImage(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestCompareGoldenHeadless(t *testing.T) {
+	t.Setenv("FINE_UPDATE_GOLDEN", "")
+	clearColor := color.RGBA{R: 30, G: 60, B: 90, A: 255}
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "clear.png")
+	wrongPath := filepath.Join(dir, "wrong.png")
+
+	// The frame is only cleared, so the expected image is the clear color
+	if err := SavePNG(solidImage(64, 48, clearColor), goldenPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePNG(solidImage(64, 48, color.RGBA{R: 255, A: 255}), wrongPath); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewHeadlessApp("golden", 64, 48)
+	app.ClearColor = clearColor
+
+	var goldenErr, wrongErr error
+	app.SetPostRenderFunc(func(app *App) {
+		goldenErr = app.CompareGolden(goldenPath, 0)
+		wrongErr = app.CompareGolden(wrongPath, 0)
+	})
+	if err := app.RunFrames(1); err != nil {
+		t.Fatalf("RunFrames: %s", err)
+	}
+
+	if goldenErr != nil {
+		t.Errorf("frame doesn't match the golden image: %s", goldenErr)
+	}
+	if wrongErr == nil {
+		t.Error("frame matches an image with a different color")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wrong_diff.png")); err != nil {
+		t.Errorf("diff image was not written: %s", err)
+	}
+}
+
+func TestScreenshotShapeHeadless(t *testing.T) {
+	clearColor := color.RGBA{A: 255}
+	rectColor := color.RGBA{R: 200, G: 100, B: 50, A: 255}
+
+	app := NewHeadlessApp("shape", 64, 48)
+	app.ClearColor = clearColor
+	// The camera is centered, so the outline goes from 22,14 to 41,33 on the screen
+	app.Rect(NewVec2(-10, -10), 20, 20, rectColor, false)
+
+	var img *image.RGBA
+	var screenshotErr error
+	app.SetPostRenderFunc(func(app *App) {
+		img, screenshotErr = app.Screenshot()
+	})
+	if err := app.RunFrames(1); err != nil {
+		t.Fatalf("RunFrames: %s", err)
+	}
+	if screenshotErr != nil {
+		t.Fatalf("Screenshot: %s", screenshotErr)
+	}
+
+	if size := img.Bounds().Size(); size != image.Pt(64, 48) {
+		t.Fatalf("screenshot size = %v, want 64x48", size)
+	}
+	for _, point := range []image.Point{{22, 14}, {41, 14}, {22, 33}, {41, 33}, {30, 14}} {
+		if got := img.RGBAAt(point.X, point.Y); got != rectColor {
+			t.Errorf("pixel %v = %v, want the rect color", point, got)
+		}
+	}
+	for _, point := range []image.Point{{0, 0}, {32, 24}, {21, 14}, {42, 33}} {
+		if got := img.RGBAAt(point.X, point.Y); got != clearColor {
+			t.Errorf("pixel %v = %v, want the clear color", point, got)
+		}
+	}
+}
+
+func TestCompareGoldenMismatch(t *testing.T) {
+	t.Setenv("FINE_UPDATE_GOLDEN", "")
+	dir := t.TempDir()
+	goldenPath := filepath.Join(dir, "golden.png")
+	diffPath := filepath.Join(dir, "golden_diff.png")
+	if err := SavePNG(solidImage(4, 3, color.RGBA{R: 100, G: 100, B: 100, A: 255}), goldenPath); err != nil {
+		t.Fatal(err)
+	}
+
+	img := solidImage(4, 3, color.RGBA{R: 100, G: 100, B: 100, A: 255})
+	img.SetRGBA(1, 0, color.RGBA{R: 105, G: 100, B: 100, A: 255})
+	img.SetRGBA(2, 2, color.RGBA{R: 100, G: 100, B: 90, A: 255})
+
+	// Both pixels are within a tolerance of 10
+	if err := CompareGolden(img, goldenPath, 10); err != nil {
+		t.Errorf("CompareGolden with tolerance 10: %s", err)
+	}
+	if _, err := os.Stat(diffPath); !os.IsNotExist(err) {
+		t.Errorf("diff image was written for matching images")
+	}
+
+	// Only the pixel that differs by 10 is outside a tolerance of 5
+	err := CompareGolden(img, goldenPath, 5)
+	if err == nil || !strings.Contains(err.Error(), "1 of 12 pixels") {
+		t.Fatalf("CompareGolden with tolerance 5 = %v, want 1 of 12 pixels differing", err)
+	}
+	diff, err := LoadPNG(diffPath)
+	if err != nil {
+		t.Fatalf("diff image was not written: %s", err)
+	}
+	red := color.RGBA{R: 255, A: 255}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			isRed := color.RGBAModel.Convert(diff.At(x, y)) == red
+			if want := x == 2 && y == 2; isRed != want {
+				t.Errorf("diff pixel %d,%d is red: %v, want %v", x, y, isRed, want)
+			}
+		}
+	}
+
+	// Images with a different size never match
+	if err := CompareGolden(solidImage(3, 3, color.RGBA{A: 255}), goldenPath, 255); err == nil {
+		t.Error("CompareGolden matched an image with a different size")
+	}
+}
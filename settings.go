@@ -0,0 +1,274 @@
+package fine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The name of the settings file in the preference directory.
+const SettingsFileName = "settings.json"
+
+// A key-value settings store saved as JSON. Values can be anything that can be
+// encoded as JSON (numbers, strings, slices, structs, key bindings...).
+type Settings struct {
+	Path     string // The path of the settings file.
+	values   map[string]json.RawMessage
+	defaults map[string]json.RawMessage
+}
+
+// Creates an empty settings store saved to a file.
+func NewSettings(path string) *Settings {
+	return &Settings{
+		Path:     path,
+		values:   make(map[string]json.RawMessage),
+		defaults: make(map[string]json.RawMessage),
+	}
+}
+
+// Returns the preference directory for an organization and app name, where
+// the app can write its files. The directory is created if it doesn't exist.
+func GetPrefPath(org, name string) (string, error) {
+	path := sdl.GetPrefPath(org, name)
+	if path == "" {
+		return "", fmt.Errorf("cannot get the preference directory: %s", sdl.GetError())
+	}
+	return path, nil
+}
+
+// Opens the settings stored in the preference directory of an organization
+// and app name, and sets app.Settings. A missing settings file is not an
+// error, the settings will be empty.
+func (app *App) OpenSettings(org, name string) (*Settings, error) {
+	dir, err := GetPrefPath(org, name)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := NewSettings(filepath.Join(dir, SettingsFileName))
+	if err := settings.Load(); err != nil {
+		return nil, err
+	}
+	app.Settings = settings
+	return settings, nil
+}
+
+// Returns the path of the backup of the settings file, which is the previous
+// successfully saved version.
+func (settings *Settings) backupPath() string {
+	return settings.Path + ".bak"
+}
+
+// Loads the settings from the settings file, replacing all values. If the file
+// is missing or corrupted, the backup of the previous save is loaded instead
+// and the corrupted file is kept with a .corrupt extension.
+func (settings *Settings) Load() error {
+	values, err := readSettingsFile(settings.Path)
+	if err == nil {
+		settings.values = values
+		return nil
+	}
+
+	if os.IsNotExist(err) {
+		// Nothing was saved yet, unless the file was lost while saving
+		if _, err := os.Stat(settings.backupPath()); err != nil {
+			settings.values = make(map[string]json.RawMessage)
+			return nil
+		}
+		log.Printf("[warn] settings file %s is missing, restoring the backup", settings.Path)
+	} else if _, corrupted := err.(*settingsCorruptedError); corrupted {
+		// Keep the corrupted file for inspection and try the backup
+		log.Printf("[warn] settings file %s is corrupted, restoring the backup: %s", settings.Path, err)
+		if err := os.Rename(settings.Path, settings.Path+".corrupt"); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	values, err = readSettingsFile(settings.backupPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[warn] settings backup %s can't be loaded, using the defaults: %s", settings.backupPath(), err)
+		}
+		values = make(map[string]json.RawMessage)
+	}
+	settings.values = values
+	return nil
+}
+
+type settingsCorruptedError struct {
+	err error
+}
+
+func (err *settingsCorruptedError) Error() string {
+	return err.err.Error()
+}
+
+func readSettingsFile(path string) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, &settingsCorruptedError{err}
+	}
+	if values == nil {
+		// The file contained null
+		values = make(map[string]json.RawMessage)
+	}
+	return values, nil
+}
+
+// Saves the settings to the settings file. The new file is written next to the
+// old one and renamed over it, so the settings file is never missing or
+// half-written. The previous version is copied to a backup first.
+func (settings *Settings) Save() error {
+	data, err := json.MarshalIndent(settings.values, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(settings.Path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// Keep a copy of the previous version as a backup, if it is valid
+	if previous, err := os.ReadFile(settings.Path); err == nil && json.Valid(previous) {
+		if err := writeFileAtomic(settings.backupPath(), previous); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(settings.Path, data)
+}
+
+// Writes a file by writing a temporary file in the same directory and renaming
+// it over the file.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), ".settings-*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// Sets the default value of a setting, returned by Get when the setting is not set.
+func (settings *Settings) SetDefault(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	settings.defaults[key] = data
+	return nil
+}
+
+// Sets the value of a setting. Call Save to write the settings to disk.
+func (settings *Settings) Set(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	settings.values[key] = data
+	return nil
+}
+
+// Decodes the value of a setting (or its default value if it isn't set) into
+// out, which should be a pointer. Returns false if the setting and its default
+// value don't exist, in which case out is unchanged.
+func (settings *Settings) Get(key string, out any) (bool, error) {
+	data, ok := settings.values[key]
+	if !ok {
+		if data, ok = settings.defaults[key]; !ok {
+			return false, nil
+		}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return true, fmt.Errorf("setting %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// Returns a setting decoded as T, or the zero value if it doesn't exist or
+// has a different type.
+func GetSetting[T any](settings *Settings, key string) T {
+	var value T
+	if _, err := settings.Get(key, &value); err != nil {
+		var zero T
+		return zero
+	}
+	return value
+}
+
+// Returns a string setting, or "" if it doesn't exist or isn't a string.
+func (settings *Settings) GetString(key string) string {
+	return GetSetting[string](settings, key)
+}
+
+// Returns an integer setting, or 0 if it doesn't exist or isn't an integer.
+func (settings *Settings) GetInt(key string) int {
+	return GetSetting[int](settings, key)
+}
+
+// Returns a number setting, or 0 if it doesn't exist or isn't a number.
+func (settings *Settings) GetFloat(key string) float64 {
+	return GetSetting[float64](settings, key)
+}
+
+// Returns a boolean setting, or false if it doesn't exist or isn't a boolean.
+func (settings *Settings) GetBool(key string) bool {
+	return GetSetting[bool](settings, key)
+}
+
+// Checks if a setting is set (default values are ignored).
+func (settings *Settings) Has(key string) bool {
+	_, ok := settings.values[key]
+	return ok
+}
+
+// Removes a setting, its default value will be used again.
+func (settings *Settings) Delete(key string) {
+	delete(settings.values, key)
+}
+
+// Removes all settings.
+func (settings *Settings) Clear() {
+	settings.values = make(map[string]json.RawMessage)
+}
+
+// Returns the keys of all settings that are set, sorted.
+func (settings *Settings) Keys() []string {
+	keys := make([]string, 0, len(settings.values))
+	for key := range settings.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
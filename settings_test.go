@@ -0,0 +1,124 @@
+package fine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game", SettingsFileName)
+	settings := NewSettings(path)
+	settings.SetDefault("volume", 0.5)
+	settings.SetDefault("name", "player")
+	settings.Set("volume", 0.8)
+	settings.Set("keys", []string{"w", "a", "s", "d"})
+	if err := settings.Save(); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+
+	loaded := NewSettings(path)
+	loaded.SetDefault("name", "player")
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if volume := loaded.GetFloat("volume"); volume != 0.8 {
+		t.Errorf("volume = %v, want 0.8", volume)
+	}
+	if keys := GetSetting[[]string](loaded, "keys"); len(keys) != 4 || keys[3] != "d" {
+		t.Errorf("keys = %v, want [w a s d]", keys)
+	}
+	if name := loaded.GetString("name"); name != "player" || loaded.Has("name") {
+		t.Errorf("name = %q, set: %v, want the default", name, loaded.Has("name"))
+	}
+	// A setting with another type gives the zero value
+	if count := loaded.GetInt("keys"); count != 0 {
+		t.Errorf("keys as an int = %d, want 0", count)
+	}
+}
+
+func TestSettingsLoadMissingFile(t *testing.T) {
+	settings := NewSettings(filepath.Join(t.TempDir(), SettingsFileName))
+	if err := settings.Load(); err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if keys := settings.Keys(); len(keys) != 0 {
+		t.Errorf("keys = %v, want none", keys)
+	}
+}
+
+func TestSettingsCorruptedRestoresBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingsFileName)
+	settings := NewSettings(path)
+	settings.Set("level", 1)
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// The second save keeps the first one as the backup
+	settings.Set("level", 2)
+	if err := settings.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"level": 3`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewSettings(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if level := loaded.GetInt("level"); level != 1 {
+		t.Errorf("level = %d, want 1 from the backup", level)
+	}
+	if data, err := os.ReadFile(path + ".corrupt"); err != nil || string(data) != `{"level": 3` {
+		t.Errorf("corrupted file = %q, %v, want it kept", data, err)
+	}
+
+	// Saving again doesn't replace the backup with the corrupted file
+	if err := loaded.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if level := loaded.GetInt("level"); level != 1 {
+		t.Errorf("level = %d after the second corruption, want 1", level)
+	}
+}
+
+func TestSettingsMissingFileRestoresBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingsFileName)
+	if err := os.WriteFile(path+".bak", []byte(`{"fullscreen": true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := NewSettings(path)
+	if err := settings.Load(); err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if !settings.GetBool("fullscreen") {
+		t.Error("the backup was not restored")
+	}
+}
+
+func TestSettingsCorruptedWithoutBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SettingsFileName)
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := NewSettings(path)
+	settings.SetDefault("volume", 0.5)
+	if err := settings.Load(); err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if keys := settings.Keys(); len(keys) != 0 {
+		t.Errorf("keys = %v, want none", keys)
+	}
+	if volume := settings.GetFloat("volume"); volume != 0.5 {
+		t.Errorf("volume = %v, want the default 0.5", volume)
+	}
+}
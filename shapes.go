@@ -42,17 +42,16 @@ func (entity *Entity) getColors() (uint8, uint8, uint8, uint8) {
 
 func baseEntity(app *App, position Vec2, w, h float64, color color.RGBA) *Entity {
 	entity := &Entity{
-		Position:         position,
-		Scene:            app.Scene,
-		Scale:            NewVec2(1, 1),
-		Visible:          true,
-		Opacity:          1,
-		Color:            color,
-		Width:            w,
-		Height:           h,
-		DoCollide:        true,
-		app:              app,
-		previousPosition: position,
+		Position:  position,
+		Scene:     app.Scene,
+		Scale:     NewVec2(1, 1),
+		Visible:   true,
+		Opacity:   1,
+		Color:     color,
+		Width:     w,
+		Height:    h,
+		DoCollide: true,
+		app:       app,
 	}
 	app.Scene.Entities = append(app.Scene.Entities, entity)
 	return entity
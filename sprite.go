@@ -155,14 +155,13 @@ func (sprite *Sprite) Render(app *App, x, y int, entity *Entity) {
 		H: sprite.Height,
 	}
 
-	var flip sdl.RendererFlip
-	switch entity.FlipDir {
-	case FLIP_NONE:
-		flip = sdl.FLIP_NONE
-	case FLIP_HORIZONTAL:
-		flip = sdl.FLIP_HORIZONTAL
-	case FLIP_VERTICAL:
-		flip = sdl.FLIP_VERTICAL
+	// The flip is a bitmask, flipping both axes is drawn as a double flip
+	var flip sdl.RendererFlip = sdl.FLIP_NONE
+	if entity.FlipDir&FLIP_HORIZONTAL != 0 {
+		flip |= sdl.FLIP_HORIZONTAL
+	}
+	if entity.FlipDir&FLIP_VERTICAL != 0 {
+		flip |= sdl.FLIP_VERTICAL
 	}
 
 	entity.Width, entity.Height = float64(sprite.Width)*entity.Scale.X, float64(sprite.Height)*entity.Scale.Y
@@ -189,6 +188,7 @@ func (sprite *Sprite) Render(app *App, x, y int, entity *Entity) {
 	sprite.Tex.SetBlendMode(sprite.BlendMode)
 	sprite.Tex.SetAlphaMod(uint8(entity.Opacity * 255))
 
+	app.Profiler.countTextureCopy()
 	app.Renderer.CopyEx(
 		sprite.Tex,
 		src,
@@ -0,0 +1,57 @@
+package fine
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type OnTextInputFunc func(text string, app *App)                      // Function that is called when text is typed. Receives the UTF-8 text and the app.
+type OnTextEditingFunc func(text string, start, length int, app *App) // Function that is called when the IME composition changes. Receives the UTF-8 composition text, the cursor position, the selection length and the app.
+
+// Starts accepting text input, OnTextInput and OnTextEditing will be called.
+// On mobile platforms this shows the on-screen keyboard.
+func (app *App) StartTextInput() *App {
+	sdl.StartTextInput()
+	return app
+}
+
+// Stops accepting text input.
+func (app *App) StopTextInput() *App {
+	sdl.StopTextInput()
+	return app
+}
+
+// Checks if text input is currently accepted.
+func (app *App) IsTextInputActive() bool {
+	return sdl.IsTextInputActive()
+}
+
+// Sets the screen rectangle where text is being typed, used by IMEs to
+// position the candidate list.
+func (app *App) SetTextInputRect(x, y, w, h int32) *App {
+	sdl.SetTextInputRect(&sdl.Rect{X: x, Y: y, W: w, H: h})
+	return app
+}
+
+// Sets the text input function (called when text is typed).
+func (app *App) SetTextInputFunc(newFunc OnTextInputFunc) *App {
+	app.OnTextInput = newFunc
+	return app
+}
+
+// Sets the text editing function (called when the IME composition changes).
+func (app *App) SetTextEditingFunc(newFunc OnTextEditingFunc) *App {
+	app.OnTextEditing = newFunc
+	return app
+}
+
+func (app *App) handleTextInputEvent(event *sdl.TextInputEvent) {
+	if app.OnTextInput != nil {
+		app.OnTextInput(event.GetText(), app)
+	}
+}
+
+func (app *App) handleTextEditingEvent(event *sdl.TextEditingEvent) {
+	if app.OnTextEditing != nil {
+		app.OnTextEditing(event.GetText(), int(event.Start), int(event.Length), app)
+	}
+}
@@ -0,0 +1,246 @@
+package fine
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type OnGestureFunc func(gesture Gesture, app *App) // Function that is called when a gesture is recognized. Receives the gesture and the app.
+
+// A finger touching the screen. Positions are in render coordinates, like the
+// mouse position.
+type Finger struct {
+	ID        int64   // The ID of the finger, unique while it touches the screen.
+	TouchID   int64   // The ID of the touch device.
+	X         float64 // X position of the finger.
+	Y         float64 // Y position of the finger.
+	Pressure  float64 // Pressure of the finger between 0 and 1.
+	StartX    float64 // X position where the finger touched the screen.
+	StartY    float64 // Y position where the finger touched the screen.
+	StartTime float64 // The app time when the finger touched the screen.
+
+	multi       bool // Another finger touched the screen while this one was down
+	longPressed bool
+}
+
+// Gesture types.
+type GestureType int
+
+const (
+	GESTURE_TAP        GestureType = 0 // A short touch.
+	GESTURE_DOUBLE_TAP GestureType = 1 // Two quick taps at the same place.
+	GESTURE_LONG_PRESS GestureType = 2 // A touch held in place.
+	GESTURE_SWIPE      GestureType = 3 // A quick movement of a finger.
+	GESTURE_PINCH      GestureType = 4 // Two or more fingers moving closer together or further apart.
+	GESTURE_ROTATE     GestureType = 5 // Two or more fingers rotating around their center.
+)
+
+// A recognized gesture. Positions and movements are in render coordinates.
+type Gesture struct {
+	Type     GestureType // The type of the gesture.
+	X        float64     // X position of the gesture (center for multi finger gestures).
+	Y        float64     // Y position of the gesture (center for multi finger gestures).
+	DeltaX   float64     // Horizontal movement of a swipe.
+	DeltaY   float64     // Vertical movement of a swipe.
+	Distance float64     // Change of the distance between the fingers of a pinch (normalized).
+	Rotation float64     // Rotation of a rotate gesture in radians.
+	Fingers  int         // The amount of fingers used in the gesture.
+}
+
+// Gesture recognition thresholds.
+type GestureSettings struct {
+	TapMaxDuration    float64 // Maximum duration of a tap in seconds (default: 0.3).
+	TapMaxDistance    float64 // Maximum movement of a tap or long press in render coordinates (default: 10).
+	DoubleTapMaxDelay float64 // Maximum time between the taps of a double tap in seconds (default: 0.3).
+	LongPressDuration float64 // Minimum duration of a long press in seconds (default: 0.5).
+	SwipeMinDistance  float64 // Minimum movement of a swipe in render coordinates (default: 50).
+	SwipeMaxDuration  float64 // Maximum duration of a swipe in seconds (default: 0.5).
+}
+
+// Returns the default gesture recognition thresholds.
+func DefaultGestureSettings() GestureSettings {
+	return GestureSettings{
+		TapMaxDuration:    0.3,
+		TapMaxDistance:    10,
+		DoubleTapMaxDelay: 0.3,
+		LongPressDuration: 0.5,
+		SwipeMinDistance:  50,
+		SwipeMaxDuration:  0.5,
+	}
+}
+
+// Touch state is updated from SDL touch events, so touch input can be tested
+// by pushing synthetic events with sdl.PushEvent.
+func (app *App) handleTouchFingerEvent(event *sdl.TouchFingerEvent) {
+	id := int64(event.FingerID)
+	x, y := app.windowToLogical(float64(event.X)*float64(app.WindowWidth), float64(event.Y)*float64(app.WindowHeight))
+
+	switch event.Type {
+	case sdl.FINGERDOWN:
+		finger := &Finger{
+			ID:        id,
+			TouchID:   int64(event.TouchID),
+			X:         x,
+			Y:         y,
+			Pressure:  float64(event.Pressure),
+			StartX:    x,
+			StartY:    y,
+			StartTime: app.Time,
+		}
+		if len(app.Fingers) > 0 {
+			finger.multi = true
+			for _, other := range app.Fingers {
+				other.multi = true
+			}
+		}
+		app.Fingers = append(app.Fingers, finger)
+		app.JustDownFingers = append(app.JustDownFingers, *finger)
+	case sdl.FINGERMOTION:
+		if finger := app.GetFinger(id); finger != nil {
+			finger.X, finger.Y = x, y
+			finger.Pressure = float64(event.Pressure)
+		}
+	case sdl.FINGERUP:
+		for idx, finger := range app.Fingers {
+			if finger.ID == id {
+				finger.X, finger.Y = x, y
+				app.Fingers = append(app.Fingers[:idx], app.Fingers[idx+1:]...)
+				app.JustUpFingers = append(app.JustUpFingers, *finger)
+				app.recognizeFingerUp(finger)
+				break
+			}
+		}
+	}
+}
+
+func (app *App) handleMultiGestureEvent(event *sdl.MultiGestureEvent) {
+	x, y := app.windowToLogical(float64(event.X)*float64(app.WindowWidth), float64(event.Y)*float64(app.WindowHeight))
+
+	if event.DDist != 0 {
+		app.addGesture(Gesture{
+			Type:     GESTURE_PINCH,
+			X:        x,
+			Y:        y,
+			Distance: float64(event.DDist),
+			Fingers:  int(event.NumFingers),
+		})
+	}
+	if event.DTheta != 0 {
+		app.addGesture(Gesture{
+			Type:     GESTURE_ROTATE,
+			X:        x,
+			Y:        y,
+			Rotation: float64(event.DTheta),
+			Fingers:  int(event.NumFingers),
+		})
+	}
+}
+
+// Recognizes taps, double taps and swipes when a finger is lifted.
+func (app *App) recognizeFingerUp(finger *Finger) {
+	if finger.multi || finger.longPressed {
+		return
+	}
+	settings := app.GestureSettings
+	duration := app.Time - finger.StartTime
+	deltaX, deltaY := finger.X-finger.StartX, finger.Y-finger.StartY
+	distance := math.Hypot(deltaX, deltaY)
+
+	switch {
+	case distance <= settings.TapMaxDistance && duration <= settings.TapMaxDuration:
+		isDoubleTap := app.hasLastTap &&
+			app.Time-app.lastTapTime <= settings.DoubleTapMaxDelay &&
+			math.Hypot(finger.X-app.lastTapX, finger.Y-app.lastTapY) <= settings.TapMaxDistance
+
+		if isDoubleTap {
+			app.hasLastTap = false
+			app.addGesture(Gesture{Type: GESTURE_DOUBLE_TAP, X: finger.X, Y: finger.Y, Fingers: 1})
+		} else {
+			app.hasLastTap = true
+			app.lastTapTime, app.lastTapX, app.lastTapY = app.Time, finger.X, finger.Y
+			app.addGesture(Gesture{Type: GESTURE_TAP, X: finger.X, Y: finger.Y, Fingers: 1})
+		}
+	case distance >= settings.SwipeMinDistance && duration <= settings.SwipeMaxDuration:
+		app.addGesture(Gesture{
+			Type:    GESTURE_SWIPE,
+			X:       finger.StartX,
+			Y:       finger.StartY,
+			DeltaX:  deltaX,
+			DeltaY:  deltaY,
+			Fingers: 1,
+		})
+	}
+}
+
+// Recognizes long presses of fingers that are still down. This is called every frame.
+func (app *App) updateGestures() {
+	for _, finger := range app.Fingers {
+		if finger.multi || finger.longPressed {
+			continue
+		}
+		moved := math.Hypot(finger.X-finger.StartX, finger.Y-finger.StartY)
+		if app.Time-finger.StartTime >= app.GestureSettings.LongPressDuration && moved <= app.GestureSettings.TapMaxDistance {
+			finger.longPressed = true
+			app.addGesture(Gesture{Type: GESTURE_LONG_PRESS, X: finger.X, Y: finger.Y, Fingers: 1})
+		}
+	}
+}
+
+func (app *App) addGesture(gesture Gesture) {
+	app.Gestures = append(app.Gestures, gesture)
+	if app.OnGesture != nil {
+		app.OnGesture(gesture, app)
+	}
+}
+
+// Returns a finger that is touching the screen, or nil if it isn't.
+func (app *App) GetFinger(id int64) *Finger {
+	for _, finger := range app.Fingers {
+		if finger.ID == id {
+			return finger
+		}
+	}
+	return nil
+}
+
+// Checks if a finger is currently touching the screen.
+func (app *App) IsFingerDown(id int64) bool {
+	return app.GetFinger(id) != nil
+}
+
+// Checks if a finger touched the screen on this frame.
+func (app *App) IsFingerJustDown(id int64) bool {
+	for _, finger := range app.JustDownFingers {
+		if finger.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a finger was lifted on this frame.
+func (app *App) IsFingerJustUp(id int64) bool {
+	for _, finger := range app.JustUpFingers {
+		if finger.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if a gesture of a type was recognized on this frame.
+func (app *App) IsGesture(gestureType GestureType) bool {
+	for _, gesture := range app.Gestures {
+		if gesture.Type == gestureType {
+			return true
+		}
+	}
+	return false
+}
+
+// Sets the gesture function (called when a gesture is recognized).
+func (app *App) SetGestureFunc(newFunc OnGestureFunc) *App {
+	app.OnGesture = newFunc
+	return app
+}
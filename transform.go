@@ -0,0 +1,303 @@
+package fine
+
+import "math"
+
+// A 2D transform of an entity. The angle is in degrees, clockwise. Like
+// textures, the transformed area is mirrored inside of its size and then
+// rotated around the pivot.
+type Transform struct {
+	Position Vec2    // Position of the top left corner before rotating.
+	Pivot    Vec2    // Rotation pivot, relative to the position.
+	Size     Vec2    // Unscaled size, flips mirror points inside of it.
+	Angle    float64 // Rotation angle in degrees.
+	Scale    Vec2    // X and Y scale.
+	FlipX    bool    // Mirrored horizontally.
+	FlipY    bool    // Mirrored vertically.
+	Opacity  float64 // Opacity between 0 and 1.
+	Visible  bool    // Visibility.
+
+	pivotCentered bool
+}
+
+// Returns true if the transform mirrors exactly one axis, which reverses the
+// direction of rotations.
+func (transform Transform) mirrored() bool {
+	return transform.FlipX != transform.FlipY
+}
+
+// Transforms a point from the space of the transform to the outer space: the
+// point is mirrored inside the size, scaled, rotated around the pivot and
+// moved by the position.
+func (transform Transform) Apply(point Vec2) Vec2 {
+	point = transform.mirror(point)
+	point.X *= transform.Scale.X
+	point.Y *= transform.Scale.Y
+
+	point = rotatePoint(point.Sub(transform.Pivot), transform.Angle)
+	point = point.Add(transform.Pivot)
+	return point.Add(transform.Position)
+}
+
+// Transforms a point from the outer space to the space of the transform. This
+// is the inverse of Apply. Axes with a scale of 0 are left unscaled.
+func (transform Transform) InverseApply(point Vec2) Vec2 {
+	point = point.Sub(transform.Position)
+	point = rotatePoint(point.Sub(transform.Pivot), -transform.Angle)
+	return transform.mirror(transform.unscale(point.Add(transform.Pivot)))
+}
+
+// Mirrors a point inside the size on the flipped axes. Mirroring twice
+// returns the original point.
+func (transform Transform) mirror(point Vec2) Vec2 {
+	if transform.FlipX {
+		point.X = transform.Size.X - point.X
+	}
+	if transform.FlipY {
+		point.Y = transform.Size.Y - point.Y
+	}
+	return point
+}
+
+// Divides a point by the scale of the transform. Axes with a scale of 0 are
+// left unscaled.
+func (transform Transform) unscale(point Vec2) Vec2 {
+	if transform.Scale.X != 0 {
+		point.X /= transform.Scale.X
+	}
+	if transform.Scale.Y != 0 {
+		point.Y /= transform.Scale.Y
+	}
+	return point
+}
+
+// Rotates a point around 0,0 by an angle in degrees.
+func rotatePoint(point Vec2, angle float64) Vec2 {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return NewVec2(point.X*cos-point.Y*sin, point.X*sin+point.Y*cos)
+}
+
+// Returns the point the transform is rotated around, relative to its
+// position. Centered pivots depend on the scale.
+func (transform Transform) pivotFor(scale Vec2) Vec2 {
+	if transform.pivotCentered {
+		return NewVec2(transform.Size.X*scale.X/2, transform.Size.Y*scale.Y/2)
+	}
+	return transform.Pivot
+}
+
+// Returns the local point that ends up on the pivot of the transform.
+func (transform Transform) pivotPoint() Vec2 {
+	return transform.mirror(transform.unscale(transform.Pivot))
+}
+
+// Combines a parent transform with a child transform that is relative to it,
+// returning the world transform of the child. Points transformed by the
+// result end up where the child and then the parent would move them.
+func (transform Transform) Combine(child Transform) Transform {
+	angle := child.Angle
+	if transform.mirrored() {
+		angle = -angle
+	}
+	world := Transform{
+		Size:          child.Size,
+		Angle:         transform.Angle + angle,
+		Scale:         transform.Scale.Mul(child.Scale),
+		FlipX:         transform.FlipX != child.FlipX,
+		FlipY:         transform.FlipY != child.FlipY,
+		Opacity:       transform.Opacity * child.Opacity,
+		Visible:       transform.Visible && child.Visible,
+		pivotCentered: child.pivotCentered,
+	}
+	world.Pivot = child.pivotFor(world.Scale)
+
+	// The pivot doesn't move when rotating, so the position follows from
+	// where the pivot point of the child ends up
+	pivot := transform.Apply(child.Apply(world.pivotPoint()))
+	world.Position = pivot.Sub(world.Pivot)
+	return world
+}
+
+// Returns the local position of a child, so that combining this transform
+// with the child gives the world position of the world transform. This is the
+// inverse of the position computed by Combine.
+func (transform Transform) localPosition(child, world Transform) Vec2 {
+	pivot := transform.InverseApply(world.Position.Add(world.Pivot))
+	child.Position = Vec2{}
+	return pivot.Sub(child.Apply(world.pivotPoint()))
+}
+
+// Returns the flip direction to draw the transform with.
+func (transform Transform) flipDirection() FlipDirection {
+	flip := FLIP_NONE
+	if transform.FlipX {
+		flip |= FLIP_HORIZONTAL
+	}
+	if transform.FlipY {
+		flip |= FLIP_VERTICAL
+	}
+	return flip
+}
+
+// Returns the transform of the entity relative to its parent.
+func (entity *Entity) LocalTransform() Transform {
+	return entity.localTransform(false)
+}
+
+// Returns the transform of the entity in the world, with the position, angle,
+// scale, flip, opacity and visibility of all parents applied.
+func (entity *Entity) WorldTransform() Transform {
+	return entity.worldTransform(false)
+}
+
+// Returns the local transform. If interpolate is true, the interpolated
+// position and angle are used for entities that interpolate.
+func (entity *Entity) localTransform(interpolate bool) Transform {
+	position, angle := entity.Position, entity.Angle
+	if interpolate && entity.Interpolate {
+		position, angle = entity.GetInterpolatedPosition(), entity.GetInterpolatedAngle()
+	}
+	local := Transform{
+		Position:      position,
+		Pivot:         entity.Pivot,
+		Size:          entity.baseSize(),
+		Angle:         angle,
+		Scale:         entity.Scale,
+		FlipX:         entity.FlipDir&FLIP_HORIZONTAL != 0,
+		FlipY:         entity.FlipDir&FLIP_VERTICAL != 0,
+		Opacity:       entity.Opacity,
+		Visible:       entity.Visible,
+		pivotCentered: entity.IsPivotCentered,
+	}
+	local.Pivot = local.pivotFor(local.Scale)
+	return local
+}
+
+// Returns the unscaled size of the entity: the size of its texture, or the
+// size of its shape.
+func (entity *Entity) baseSize() Vec2 {
+	if entity.Texture != nil {
+		return NewVec2(float64(entity.Texture.Width), float64(entity.Texture.Height))
+	}
+	return NewVec2(entity.Width, entity.Height)
+}
+
+func (entity *Entity) worldTransform(interpolate bool) Transform {
+	local := entity.localTransform(interpolate)
+	if entity.Parent == nil {
+		return local
+	}
+	return entity.Parent.worldTransform(interpolate).Combine(local)
+}
+
+// Returns the position of the entity in the world.
+func (entity *Entity) WorldPosition() Vec2 {
+	if entity.Parent == nil {
+		return entity.Position
+	}
+	return entity.WorldTransform().Position
+}
+
+// Moves the entity to a position in the world.
+func (entity *Entity) SetWorldPosition(position Vec2) *Entity {
+	if entity.Parent == nil {
+		entity.Position = position
+		return entity
+	}
+	world := entity.WorldTransform()
+	world.Position = position
+	entity.Position = entity.Parent.WorldTransform().localPosition(entity.LocalTransform(), world)
+	return entity
+}
+
+// Converts a point relative to the entity (like the position of a child) to a
+// world position.
+func (entity *Entity) LocalToWorld(point Vec2) Vec2 {
+	return entity.WorldTransform().Apply(point)
+}
+
+// Converts a world position to a point relative to the entity.
+func (entity *Entity) WorldToLocal(point Vec2) Vec2 {
+	return entity.WorldTransform().InverseApply(point)
+}
+
+// Sets the position, angle, scale and flip of the entity so its world
+// transform matches the given world transform. Opacity and visibility are not
+// changed.
+func (entity *Entity) setWorldTransform(world Transform) {
+	local := entity.LocalTransform()
+	local.Angle, local.Scale = world.Angle, world.Scale
+	local.FlipX, local.FlipY = world.FlipX, world.FlipY
+	if entity.Parent != nil {
+		parent := entity.Parent.WorldTransform()
+		local.Angle = world.Angle - parent.Angle
+		if parent.mirrored() {
+			local.Angle = -local.Angle
+		}
+		local.Scale = parent.unscale(world.Scale)
+		local.FlipX = world.FlipX != parent.FlipX
+		local.FlipY = world.FlipY != parent.FlipY
+		local.Pivot = local.pivotFor(local.Scale)
+		local.Position = parent.localPosition(local, world)
+	} else {
+		local.Position = world.Position
+	}
+
+	entity.Position = local.Position
+	entity.Angle = local.Angle
+	entity.Scale = local.Scale
+	entity.FlipDir = local.flipDirection()
+}
+
+// Checks if the entity is ancestor or one of its parents.
+func (entity *Entity) isDescendantOf(ancestor *Entity) bool {
+	for parent := entity.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Removes the entity from the children of its parent.
+func (entity *Entity) detachFromParent() {
+	if entity.Parent == nil {
+		return
+	}
+	children := entity.Parent.Children
+	for idx, child := range children {
+		if child == entity {
+			entity.Parent.Children = append(children[:idx], children[idx+1:]...)
+			break
+		}
+	}
+	entity.Parent = nil
+}
+
+// The fields of an entity that are replaced by the world transform while drawing.
+type drawnTransform struct {
+	position Vec2
+	angle    float64
+	scale    Vec2
+	flip     FlipDirection
+	opacity  float64
+}
+
+// Replaces the transform of the entity with a world transform for drawing and
+// returns the previous values.
+func (entity *Entity) swapTransform(world Transform) drawnTransform {
+	previous := drawnTransform{entity.Position, entity.Angle, entity.Scale, entity.FlipDir, entity.Opacity}
+	entity.Position = world.Position
+	entity.Scale = world.Scale
+	entity.Opacity = world.Opacity
+	entity.Angle = world.Angle
+	entity.FlipDir = world.flipDirection()
+	return previous
+}
+
+func (entity *Entity) restoreTransform(previous drawnTransform) {
+	entity.Position = previous.position
+	entity.Angle = previous.angle
+	entity.Scale = previous.scale
+	entity.FlipDir = previous.flip
+	entity.Opacity = previous.opacity
+}
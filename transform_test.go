@@ -0,0 +1,161 @@
+package fine
+
+import (
+	"math"
+	"testing"
+)
+
+func vecNear(a, b Vec2) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func newTestEntity(position Vec2, width, height int32) *Entity {
+	return &Entity{
+		Position: position,
+		Scale:    NewVec2(1, 1),
+		Opacity:  1,
+		Visible:  true,
+		Texture:  &Sprite{Width: width, Height: height},
+	}
+}
+
+var testTransforms = []Transform{
+	{Scale: NewVec2(1, 1)},
+	{Position: NewVec2(5, -3), Scale: NewVec2(2, 0.5)},
+	{Position: NewVec2(10, 20), Pivot: NewVec2(4, 6), Angle: 30, Scale: NewVec2(1, 1)},
+	{Position: NewVec2(100, 50), Size: NewVec2(40, 20), Scale: NewVec2(1, 1), FlipX: true},
+	{Position: NewVec2(-7, 3), Size: NewVec2(8, 6), Pivot: NewVec2(8, 6), Angle: -75, Scale: NewVec2(2, 2), FlipX: true, FlipY: true},
+	{Position: NewVec2(1, 2), Size: NewVec2(16, 16), Pivot: NewVec2(12, 12), Angle: 200, Scale: NewVec2(1.5, 1.5), FlipY: true},
+}
+
+var testPoints = []Vec2{NewVec2(0, 0), NewVec2(10, 0), NewVec2(-3, 7), NewVec2(40, 20)}
+
+func TestTransformInverseApply(t *testing.T) {
+	for idx, transform := range testTransforms {
+		for _, point := range testPoints {
+			if got := transform.InverseApply(transform.Apply(point)); !vecNear(got, point) {
+				t.Errorf("transform %d: InverseApply(Apply(%v)) = %v", idx, point, got)
+			}
+		}
+	}
+}
+
+func TestTransformFlipMirrorsInsideSize(t *testing.T) {
+	transform := Transform{Position: NewVec2(100, 50), Size: NewVec2(40, 20), Scale: NewVec2(1, 1), FlipX: true}
+	if got, want := transform.Apply(NewVec2(10, 0)), NewVec2(130, 50); !vecNear(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+
+	transform.FlipX, transform.FlipY = false, true
+	if got, want := transform.Apply(NewVec2(10, 5)), NewVec2(110, 65); !vecNear(got, want) {
+		t.Errorf("Apply = %v, want %v", got, want)
+	}
+}
+
+func TestTransformCombine(t *testing.T) {
+	// Scales are uniform, so the combined transform is exact
+	for parentIdx, parent := range testTransforms {
+		for childIdx, child := range testTransforms {
+			if child.Scale.X != child.Scale.Y || parent.Scale.X != parent.Scale.Y {
+				continue
+			}
+			world := parent.Combine(child)
+			for _, point := range testPoints {
+				want := parent.Apply(child.Apply(point))
+				if got := world.Apply(point); !vecNear(got, want) {
+					t.Errorf("parent %d, child %d: Combine(...).Apply(%v) = %v, want %v", parentIdx, childIdx, point, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestChildOfFlippedParent(t *testing.T) {
+	parent := newTestEntity(NewVec2(100, 50), 40, 20)
+	parent.FlipDir = FLIP_HORIZONTAL
+	child := newTestEntity(NewVec2(10, 0), 4, 4)
+	child.Parent = parent
+
+	// The child is mirrored inside the parent, like the parent texture
+	if got, want := child.WorldPosition(), NewVec2(126, 50); !vecNear(got, want) {
+		t.Errorf("WorldPosition = %v, want %v", got, want)
+	}
+	if world := child.WorldTransform(); !world.FlipX || world.FlipY {
+		t.Errorf("world flip = %v, %v, want true, false", world.FlipX, world.FlipY)
+	}
+}
+
+func TestChildOfRotatedParent(t *testing.T) {
+	parent := newTestEntity(NewVec2(0, 0), 20, 20)
+	parent.IsPivotCentered = true
+	parent.Angle = 90
+	child := newTestEntity(NewVec2(20, 0), 0, 0)
+	child.Parent = parent
+
+	// The top right corner of the parent is rotated around its center
+	if got, want := child.WorldPosition(), NewVec2(20, 20); !vecNear(got, want) {
+		t.Errorf("WorldPosition = %v, want %v", got, want)
+	}
+	if got := child.WorldTransform().Angle; got != 90 {
+		t.Errorf("world angle = %v, want 90", got)
+	}
+}
+
+func TestFlipBothKeepsAngle(t *testing.T) {
+	entity := newTestEntity(NewVec2(0, 0), 10, 10)
+	entity.FlipDir = FLIP_BOTH
+	entity.Angle = 10
+
+	world := entity.WorldTransform()
+	if world.flipDirection() != FLIP_BOTH || world.Angle != 10 {
+		t.Errorf("flip = %v, angle = %v, want FLIP_BOTH, 10", world.flipDirection(), world.Angle)
+	}
+}
+
+func TestParentToKeepsWorldTransform(t *testing.T) {
+	parent := newTestEntity(NewVec2(30, -10), 24, 12)
+	parent.FlipDir = FLIP_BOTH
+	parent.Angle = 40
+	parent.Scale = NewVec2(1.5, 1.5)
+	parent.Pivot = NewVec2(5, 3)
+
+	child := newTestEntity(NewVec2(70, 80), 8, 6)
+	child.IsPivotCentered = true
+	child.Angle = -20
+	child.Scale = NewVec2(2, 2)
+
+	before := child.WorldTransform()
+	child.ParentTo(parent)
+	after := child.WorldTransform()
+	if !vecNear(after.Position, before.Position) || !vecNear(after.Scale, before.Scale) ||
+		math.Abs(after.Angle-before.Angle) > 1e-9 || after.FlipX != before.FlipX || after.FlipY != before.FlipY {
+		t.Errorf("world transform changed when parenting: %+v, want %+v", after, before)
+	}
+	if child.FlipDir != FLIP_BOTH {
+		t.Errorf("local flip = %v, want FLIP_BOTH", child.FlipDir)
+	}
+
+	child.RemoveParent()
+	if !vecNear(child.Position, before.Position) || math.Abs(child.Angle-before.Angle) > 1e-9 ||
+		child.FlipDir != FLIP_NONE || !vecNear(child.Scale, before.Scale) {
+		t.Errorf("transform changed when unparenting: %v, %v, %v", child.Position, child.Angle, child.FlipDir)
+	}
+}
+
+func TestSetWorldPosition(t *testing.T) {
+	parent := newTestEntity(NewVec2(-5, 15), 30, 10)
+	parent.FlipDir = FLIP_HORIZONTAL
+	parent.Angle = 135
+	parent.IsPivotCentered = true
+
+	child := newTestEntity(NewVec2(0, 0), 6, 6)
+	child.Pivot = NewVec2(2, 1)
+	child.Angle = 15
+	child.ParentTo(parent)
+
+	target := NewVec2(42, -17)
+	child.SetWorldPosition(target)
+	if got := child.WorldPosition(); !vecNear(got, target) {
+		t.Errorf("WorldPosition = %v, want %v", got, target)
+	}
+}
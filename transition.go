@@ -0,0 +1,118 @@
+package fine
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// A transition between two scenes. The scene is changed halfway through the
+// transition, so the first half should hide the old scene and the second half
+// should reveal the new scene.
+type Transition interface {
+	Length() float64                 // The duration of the transition in seconds.
+	Draw(progress float64, app *App) // Draws the transition over the frame. Progress goes from 0 to 1.
+}
+
+// Fades the screen to a color and back.
+type FadeTransition struct {
+	Duration float64    // The duration of the transition in seconds.
+	Color    color.RGBA // The color to fade to.
+}
+
+// Creates a fade to black that lasts duration seconds.
+func NewFadeTransition(duration float64) *FadeTransition {
+	return &FadeTransition{Duration: duration, Color: color.RGBA{0, 0, 0, 255}}
+}
+
+func (fade *FadeTransition) Length() float64 {
+	return fade.Duration
+}
+
+func (fade *FadeTransition) Draw(progress float64, app *App) {
+	opacity := 1 - math.Abs(progress*2-1)
+	alpha := uint8(math.Round(float64(fade.Color.A) * opacity))
+	app.fillTransitionRect(&sdl.Rect{X: 0, Y: 0, W: app.Width, H: app.Height}, fade.Color, alpha)
+}
+
+type WipeDirection int
+
+const (
+	WIPE_RIGHT WipeDirection = 0 // Wipe from the left edge to the right edge.
+	WIPE_LEFT  WipeDirection = 1 // Wipe from the right edge to the left edge.
+	WIPE_DOWN  WipeDirection = 2 // Wipe from the top edge to the bottom edge.
+	WIPE_UP    WipeDirection = 3 // Wipe from the bottom edge to the top edge.
+)
+
+// Covers the screen with a color moving across it, then uncovers it in the
+// same direction.
+type WipeTransition struct {
+	Duration  float64       // The duration of the transition in seconds.
+	Color     color.RGBA    // The color of the wipe.
+	Direction WipeDirection // The direction the wipe moves in.
+}
+
+// Creates a black wipe that lasts duration seconds.
+func NewWipeTransition(duration float64, direction WipeDirection) *WipeTransition {
+	return &WipeTransition{Duration: duration, Color: color.RGBA{0, 0, 0, 255}, Direction: direction}
+}
+
+func (wipe *WipeTransition) Length() float64 {
+	return wipe.Duration
+}
+
+func (wipe *WipeTransition) Draw(progress float64, app *App) {
+	// The covered part of the screen, from 0 to 1 in the direction of the wipe
+	start := math.Max(progress*2-1, 0)
+	end := math.Min(progress*2, 1)
+	if end <= start {
+		return
+	}
+
+	var rect sdl.Rect
+	switch wipe.Direction {
+	case WIPE_RIGHT, WIPE_LEFT:
+		x0, x1 := start, end
+		if wipe.Direction == WIPE_LEFT {
+			x0, x1 = 1-end, 1-start
+		}
+		rect = sdl.Rect{
+			X: int32(math.Floor(x0 * float64(app.Width))),
+			W: int32(math.Ceil((x1 - x0) * float64(app.Width))),
+			H: app.Height,
+		}
+	default:
+		y0, y1 := start, end
+		if wipe.Direction == WIPE_UP {
+			y0, y1 = 1-end, 1-start
+		}
+		rect = sdl.Rect{
+			Y: int32(math.Floor(y0 * float64(app.Height))),
+			W: app.Width,
+			H: int32(math.Ceil((y1 - y0) * float64(app.Height))),
+		}
+	}
+	app.fillTransitionRect(&rect, wipe.Color, wipe.Color.A)
+}
+
+// Fills a rect with a color blended over the frame.
+func (app *App) fillTransitionRect(rect *sdl.Rect, fillColor color.RGBA, alpha uint8) {
+	if alpha == 0 {
+		return
+	}
+	prevR, prevG, prevB, prevA, err := app.Renderer.GetDrawColor()
+	if err != nil {
+		prevR, prevG, prevB, prevA = 0, 0, 0, 0
+	}
+	var prevBlendMode sdl.BlendMode
+	if err := app.Renderer.GetDrawBlendMode(&prevBlendMode); err != nil {
+		prevBlendMode = sdl.BLENDMODE_NONE
+	}
+
+	app.Renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	app.Renderer.SetDrawColor(fillColor.R, fillColor.G, fillColor.B, alpha)
+	app.Renderer.FillRect(rect)
+	app.Renderer.SetDrawColor(prevR, prevG, prevB, prevA)
+	app.Renderer.SetDrawBlendMode(prevBlendMode)
+}
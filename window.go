@@ -2,6 +2,8 @@ package fine
 
 import (
 	"fmt"
+	"log"
+	"os"
 	"runtime"
 	"time"
 
@@ -10,104 +12,319 @@ import (
 
 // Creates a new window and starts the draw loop.
 func (app *App) Run() error {
+	return app.run(nil)
+}
+
+// Creates a new window and draws exactly n frames, then stops the app.
+func (app *App) RunFrames(frames int) error {
+	return app.run(func(app *App) bool {
+		return app.Frame >= frames
+	})
+}
+
+// Creates a new window and draws frames until the stop function returns true or
+// the app is closed. The stop function is checked before every frame.
+func (app *App) RunUntil(stop StopFunc) error {
+	return app.run(stop)
+}
+
+func (app *App) run(stop StopFunc) error {
+	if app.parent != nil {
+		return errRunWindow
+	}
+
 	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
 
 	defer app.FreeSprites()
 	defer app.UnloadFontLibrary()
+	defer app.StopRecording()
+
+	// Use the dummy drivers in headless mode, they don't need a display or an
+	// audio device. SDL only reads the drivers while initializing, so the
+	// environment of the process is restored right after
+	var restoreEnv []func()
+	if app.Headless {
+		restoreEnv = append(restoreEnv, setEnvTemporarily("SDL_VIDEODRIVER", "dummy"))
+	}
+	if app.NullAudio {
+		restoreEnv = append(restoreEnv, setEnvTemporarily("SDL_AUDIODRIVER", "dummy"))
+	}
 
 	// Initialize SDL
-	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
+	err := sdl.Init(sdl.INIT_EVERYTHING)
+	for _, restore := range restoreEnv {
+		restore()
+	}
+	if err != nil {
 		return err
 	}
 	defer sdl.Quit()
+	defer app.closeGamepads()
 
-	// Create window
-	if len(app.WindowFlags) < 1 {
-		return fmt.Errorf("cannot initialize window without any window flags")
-	}
-
-	// Get window flags
-	var finalFlags uint32
-	for _, flag := range app.WindowFlags {
-		finalFlags |= uint32(flag)
-	}
+	addGamepadMappings(app.gamepadMappings)
+	app.gamepadMappings = nil
 
 	if app.ScaleQuality != 0 {
 		sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, fmt.Sprint(app.ScaleQuality))
 	}
 
-	// Create window
-	window, err := sdl.CreateWindow(
-		app.Title,
-		sdl.WINDOWPOS_UNDEFINED,
-		sdl.WINDOWPOS_UNDEFINED,
-		app.Width,
-		app.Height,
-		finalFlags,
-	)
-	if err != nil {
+	// Create window and renderer
+	if err := app.createWindow(); err != nil {
 		return err
 	}
-	defer window.Destroy()
-	app.Window = window
+	defer app.destroyWindow()
 
-	// Create renderer
-	if app.Renderer, err = sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED); err != nil {
-		return err
+	// Apply the cursor set before the window was created. A missing cursor
+	// shouldn't stop the app
+	if err := app.applyCursor(); err != nil {
+		log.Printf("[warn] failed to set the mouse cursor: %s", err)
+	}
+	if app.CursorHidden {
+		sdl.ShowCursor(sdl.DISABLE)
+	}
+	defer app.freeCursors()
+
+	// Start with the current mouse position, the events only report changes.
+	// Replays start from the recorded events instead
+	if !app.IsReplaying() {
+		x, y, _ := sdl.GetMouseState()
+		app.setMousePos(x, y)
 	}
-	app.Renderer.Clear()
-	defer app.Renderer.Destroy()
 
 	// Start draw loop
-	startTime := time.Now()
-	if err := sdl.GLSetSwapInterval(app.SwapInterval); err != nil {
+	if app.Clock == nil {
+		app.Clock = NewRealClock()
+	}
+	if !app.Headless {
+		if err := sdl.GLSetSwapInterval(app.SwapInterval); err != nil {
+			return err
+		}
+	}
+	if err := app.openWindows(); err != nil {
 		return err
 	}
+	defer app.closeWindows()
+
 	app.Running = true
+	app.Frame = 0
+	app.accumulator = 0
+	app.frameStart = time.Time{}
+	app.FrameStats.Reset()
+	defer func() { app.Running = false }()
+
+	if err := app.start(); err != nil {
+		return err
+	}
+	defer app.stop()
 
 	for app.Running {
-		// app.ScrollDeltaX, app.ScrollDeltaY = 0, 0
-		app.IsScrolling = false
-
-		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-			switch t := event.(type) {
-			case *sdl.QuitEvent:
-				// Ask close function if we need to close
-				doCloseResponse := true
-				if app.OnClose != nil {
-					doCloseResponse = app.OnClose()
-				}
+		if stop != nil && stop(app) {
+			break
+		}
+
+		app.Profiler.beginFrame(app.Frame)
+		eventsStart := app.Profiler.begin()
+		app.resetFrameInput()
 
-				if !app.IgnoreClose && doCloseResponse {
-					app.Running = false
-					break
+		if app.replayer != nil {
+			// Only let the user close the app while replaying
+			for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+				if _, ok := event.(*sdl.QuitEvent); ok {
+					app.handleEvent(event)
 				}
-			case *sdl.KeyboardEvent:
-				app.handleKeyboardEvent(t)
-			case *sdl.MouseButtonEvent:
-				app.handleMouseButtonEvent(t)
-			case *sdl.MouseWheelEvent:
-				app.ScrollDeltaX, app.ScrollDeltaY = t.PreciseX, t.PreciseY
-				app.IsScrolling = true
+			}
+			app.replayFrame()
+		} else {
+			for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+				if app.recorder != nil {
+					app.recorder.add(event)
+				}
+				app.handleEvent(event)
 			}
 		}
+		app.flushAllDroppedFiles()
+		app.Profiler.end(PROFILE_EVENTS, eventsStart)
 
 		// Update
-		app.Time = time.Since(startTime).Seconds()
+		app.Time = app.Clock.Now()
 		app.DeltaTime = app.Time - app.PreviousFrameTime
 		app.PreviousFrameTime = app.Time
-		app.GetWindowSize() // This will update the window size for us :)
+		if app.audioPaused.Load() {
+			// Audio doesn't advance while paused, see audio.Ended
+			app.pausedTime += app.DeltaTime
+		}
+		if app.Frame > 0 {
+			app.FrameStats.Add(app.DeltaTime)
+		}
+		if app.recorder != nil {
+			if err := app.recorder.writeFrame(app.DeltaTime); err != nil {
+				return err
+			}
+		}
+		app.updateRenderSize()
 
 		// Draw
 		if err := app.DrawFrame(); err != nil {
 			return err
 		}
+		app.drawProfilerOverlay()
+		presentStart := app.Profiler.begin()
 		app.Renderer.Present()
+		app.Profiler.end(PROFILE_PRESENT, presentStart)
+		app.Profiler.endFrame()
+		app.Frame++
+
+		if err := app.drawWindows(); err != nil {
+			return err
+		}
+		app.limitFrameRate()
 	}
 
 	return nil
 }
 
+// Sets an environment variable and returns a function that restores its
+// previous value.
+func setEnvTemporarily(key, value string) func() {
+	previous, wasSet := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if wasSet {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// Creates the SDL window and renderer of the app.
+func (app *App) createWindow() error {
+	if len(app.WindowFlags) < 1 {
+		return fmt.Errorf("cannot initialize window without any window flags")
+	}
+
+	// Get window flags
+	var finalFlags uint32
+	for _, flag := range app.WindowFlags {
+		finalFlags |= uint32(flag)
+	}
+	// A fullscreen window flag sets the fullscreen mode, unless it was set
+	// with app.SetFullscreen
+	if app.Fullscreen == FULLSCREEN_WINDOWED {
+		app.Fullscreen = fullscreenModeOf(finalFlags)
+	}
+	finalFlags = finalFlags&^sdl.WINDOW_FULLSCREEN_DESKTOP | app.Fullscreen.flags()
+	if app.Headless {
+		// The dummy video driver can't create OpenGL or Vulkan windows
+		finalFlags &^= sdl.WINDOW_OPENGL | sdl.WINDOW_VULKAN
+	}
+
+	// Create window. The size of the previous window is kept if the app runs
+	// again, app.Width and app.Height may be the logical size by then
+	width, height := app.Width, app.Height
+	if app.WindowWidth > 0 && app.WindowHeight > 0 {
+		width, height = app.WindowWidth, app.WindowHeight
+	}
+	x := int32(sdl.WINDOWPOS_UNDEFINED_MASK | app.Display)
+	y := x
+	if app.windowPosition != nil {
+		x, y = app.windowPosition.X, app.windowPosition.Y
+	}
+	window, err := sdl.CreateWindow(
+		app.Title,
+		x,
+		y,
+		width,
+		height,
+		finalFlags,
+	)
+	if err != nil {
+		return err
+	}
+	app.Window = window
+	app.applyWindowSettings()
+
+	if app.Fullscreen == FULLSCREEN_EXCLUSIVE && app.fullscreenDisplayMode != nil {
+		if err := window.SetDisplayMode(app.fullscreenDisplayMode); err != nil {
+			window.Destroy()
+			app.Window = nil
+			return err
+		}
+	}
+
+	// Create renderer
+	var rendererFlags uint32 = sdl.RENDERER_ACCELERATED
+	if app.Headless {
+		rendererFlags = sdl.RENDERER_SOFTWARE
+	}
+	if app.Renderer, err = sdl.CreateRenderer(window, -1, rendererFlags); err != nil {
+		window.Destroy()
+		app.Window = nil
+		return err
+	}
+	app.scalingChanged = true
+	app.updateRenderSize()
+	app.Renderer.Clear()
+	return nil
+}
+
+// Destroys the SDL window and renderer of the app.
+func (app *App) destroyWindow() {
+	if app.Renderer != nil {
+		app.Renderer.Destroy()
+		app.Renderer = nil
+	}
+	if app.Window != nil {
+		app.Window.Destroy()
+		app.Window = nil
+	}
+}
+
+// Handles an SDL event.
+func (app *App) handleEvent(event sdl.Event) {
+	// Send window events to the window they belong to
+	if window := app.getEventApp(event); window != app {
+		window.handleEvent(event)
+		return
+	}
+
+	switch t := event.(type) {
+	case *sdl.QuitEvent:
+		// Ask close function if we need to close
+		app.requestClose()
+	case *sdl.KeyboardEvent:
+		app.handleKeyboardEvent(t)
+	case *sdl.MouseButtonEvent:
+		app.handleMouseButtonEvent(t)
+	case *sdl.MouseMotionEvent:
+		app.handleMouseMotionEvent(t)
+	case *sdl.MouseWheelEvent:
+		app.ScrollDeltaX, app.ScrollDeltaY = t.PreciseX, t.PreciseY
+		app.IsScrolling = true
+	case *sdl.TextInputEvent:
+		app.handleTextInputEvent(t)
+	case *sdl.TextEditingEvent:
+		app.handleTextEditingEvent(t)
+	case *sdl.TouchFingerEvent:
+		app.handleTouchFingerEvent(t)
+	case *sdl.MultiGestureEvent:
+		app.handleMultiGestureEvent(t)
+	case *sdl.ControllerDeviceEvent:
+		app.handleControllerDeviceEvent(t)
+	case *replayedGamepadEvent:
+		app.addReplayedGamepad(int(t.Which))
+	case *sdl.ControllerButtonEvent:
+		app.handleControllerButtonEvent(t)
+	case *sdl.ControllerAxisEvent:
+		app.handleControllerAxisEvent(t)
+	case *sdl.WindowEvent:
+		app.handleWindowEvent(t)
+	case *sdl.DropEvent:
+		app.handleDropEvent(t)
+	}
+}
+
 type WindowFlag int
 
 const (
@@ -0,0 +1,191 @@
+package fine
+
+import (
+	"errors"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	errRunWindow error = errors.New("cannot run an additional window, it is drawn by the app that opened it")
+)
+
+// Opens an additional window. The returned app has its own window, renderer,
+// scene and camera and receives the input events of its window, but it is
+// driven by the main loop of this app, so don't call Run on it. Sprites have
+// to be loaded with the app of the window they are drawn in.
+// If this app is not running yet, the window is opened when it starts.
+func (app *App) NewWindow(title string, width, height int32) (*App, error) {
+	window := newApp(title, width, height)
+	window.parent = app
+	window.Headless = app.Headless
+	window.NullAudio = true
+
+	if app.Running {
+		if err := window.openWindow(); err != nil {
+			return nil, err
+		}
+	}
+	app.Windows = append(app.Windows, window)
+	return window, nil
+}
+
+// Returns the app that opened this window, or nil if this is the main app.
+func (app *App) GetParent() *App {
+	return app.parent
+}
+
+// Opens the SDL window of an additional window.
+func (app *App) openWindow() error {
+	if err := app.createWindow(); err != nil {
+		return err
+	}
+	app.Running = true
+	return nil
+}
+
+// Closes the SDL window of an additional window and frees its sprites.
+func (app *App) closeWindow() {
+	app.Running = false
+	app.FreeSprites()
+	app.destroyWindow()
+}
+
+// Opens all additional windows that were created before the app started.
+func (app *App) openWindows() error {
+	for _, window := range app.Windows {
+		if window.Window != nil {
+			continue
+		}
+		if err := window.openWindow(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closes all additional windows.
+func (app *App) closeWindows() {
+	for _, window := range app.Windows {
+		window.closeWindow()
+	}
+	app.Windows = nil
+}
+
+// Draws all additional windows and removes the ones that were closed.
+func (app *App) drawWindows() error {
+	for _, window := range app.Windows {
+		if !window.Running {
+			continue
+		}
+
+		window.Time = app.Time
+		window.DeltaTime = app.DeltaTime
+		window.PreviousFrameTime = app.PreviousFrameTime
+		window.updateRenderSize()
+
+		if err := window.DrawFrame(); err != nil {
+			return err
+		}
+		window.Renderer.Present()
+		window.Frame++
+	}
+
+	// Remove closed windows
+	open := app.Windows[:0]
+	for _, window := range app.Windows {
+		if window.Running {
+			open = append(open, window)
+		} else {
+			window.closeWindow()
+		}
+	}
+	app.Windows = open
+	return nil
+}
+
+// Returns the app whose window has an ID, or nil if there is none.
+func (app *App) getWindowApp(id uint32) *App {
+	if app.Window != nil {
+		if windowID, err := app.Window.GetID(); err == nil && windowID == id {
+			return app
+		}
+	}
+	for _, window := range app.Windows {
+		if window.Window == nil {
+			continue
+		}
+		if windowID, err := window.Window.GetID(); err == nil && windowID == id {
+			return window
+		}
+	}
+	return nil
+}
+
+// Returns the app an event should be handled by, based on its window ID.
+func (app *App) getEventApp(event sdl.Event) *App {
+	if len(app.Windows) == 0 {
+		return app
+	}
+
+	var windowID uint32
+	switch t := event.(type) {
+	case *sdl.KeyboardEvent:
+		windowID = t.WindowID
+	case *sdl.TextInputEvent:
+		windowID = t.WindowID
+	case *sdl.TextEditingEvent:
+		windowID = t.WindowID
+	case *sdl.MouseButtonEvent:
+		windowID = t.WindowID
+	case *sdl.MouseMotionEvent:
+		windowID = t.WindowID
+	case *sdl.MouseWheelEvent:
+		windowID = t.WindowID
+	case *sdl.WindowEvent:
+		windowID = t.WindowID
+	case *sdl.DropEvent:
+		windowID = t.WindowID
+	default:
+		return app
+	}
+
+	if window := app.getWindowApp(windowID); window != nil {
+		return window
+	}
+	return app
+}
+
+// Resets the input state that only lasts for a single frame.
+func (app *App) resetFrameInput() {
+	// app.ScrollDeltaX, app.ScrollDeltaY = 0, 0
+	app.IsScrolling = false
+	// Carry the fractions of the movement over to the next frame
+	app.mouseDeltaX -= float64(app.MouseDeltaX)
+	app.mouseDeltaY -= float64(app.MouseDeltaY)
+	app.MouseDeltaX, app.MouseDeltaY = 0, 0
+
+	for _, window := range app.Windows {
+		window.resetFrameInput()
+	}
+}
+
+// Calls the file drop functions of this app and all additional windows.
+func (app *App) flushAllDroppedFiles() {
+	app.flushDroppedFiles()
+	for _, window := range app.Windows {
+		window.flushDroppedFiles()
+	}
+}
+
+// Asks the close function if the window should close and closes it.
+func (app *App) requestClose() {
+	doCloseResponse := true
+	if app.OnClose != nil {
+		doCloseResponse = app.OnClose()
+	}
+
+	if !app.IgnoreClose && doCloseResponse {
+		app.Running = false
+	}
+}